@@ -2,11 +2,16 @@ package easemob
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"path"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 	"uw/ureq"
 )
 
@@ -29,22 +34,25 @@ func (eb *Easemob) RefreshToken(ctx context.Context, ttl int) error {
 		return fmt.Errorf("get client error: %w", e)
 	}
 
-	res, e := c.Post(eb.GetURL("token").String()).
+	eb.mu.RLock()
+	req := &refreshTokenReq{
+		GrantType:    "client_credentials",
+		ClientId:     eb.clientId,
+		ClientSecret: eb.clientSecret,
+		TTL:          ttl,
+	}
+	eb.mu.RUnlock()
+
+	res, e := eb.end(c.Post(eb.GetTokenURL()).
 		Set(ureq.ContentType, "application/json").
 		Set(ureq.Accept, "application/json").
-		Send(&refreshTokenReq{
-			GrantType:    "client_credentials",
-			ClientId:     eb.clientId,
-			ClientSecret: eb.clientSecret,
-			TTL:          ttl,
-		}).End()
+		Send(req))
 	if e != nil {
 		return fmt.Errorf("refresh token error: %w", e)
 	}
 
 	if !res.OK() {
-		text, _ := res.Text()
-		return fmt.Errorf("refresh token error: %s, %s", res.Status, text)
+		return newResponseError("refresh token", res)
 	}
 
 	resp := &refreshTokenResp{}
@@ -59,24 +67,165 @@ func (eb *Easemob) RefreshToken(ctx context.Context, ttl int) error {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
+	eb.application = resp.Application
 	eb.accessToken = resp.AccessToken
 	eb.accessTokenExpiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
 	return nil
 }
 
+// RotateClientSecret 更换 client_secret 并立即使用新的 client_secret 刷新 Token
+// 刷新失败 (例如新的 client_secret 无效, 返回的错误满足 errors.Is(e, ErrUnauthorized)) 时恢复为原来的 client_secret,
+// 已有的 Token 不受影响, 客户端可以继续正常使用
+// newSecret: 新的 client_secret
+func (eb *Easemob) RotateClientSecret(ctx context.Context, newSecret string) error {
+	if len(strings.TrimSpace(newSecret)) < 1 {
+		return errors.New("rotate client secret error: secret is empty")
+	}
+
+	eb.mu.Lock()
+	oldSecret := eb.clientSecret
+	eb.clientSecret = newSecret
+	eb.mu.Unlock()
+
+	if e := eb.RefreshToken(ctx, 0); e != nil {
+		eb.mu.Lock()
+		if eb.clientSecret == newSecret {
+			eb.clientSecret = oldSecret
+		}
+		eb.mu.Unlock()
+
+		return fmt.Errorf("rotate client secret error: %w", e)
+	}
+
+	return nil
+}
+
+type AppIdentity struct {
+	Application string    // 当前 App 的 UUID
+	OrgName     string    // 组织名称
+	AppName     string    // 应用名称
+	ExpiresAt   time.Time // Token 过期时间
+}
+
+// WhoAmI 使用当前凭证重新获取 Token, 并返回 App 的身份信息, 可用作启动时的健康检查
+// 凭证无效时返回的错误满足 errors.Is(e, ErrUnauthorized)
+func (eb *Easemob) WhoAmI(ctx context.Context) (*AppIdentity, error) {
+	if e := eb.RefreshToken(ctx, 0); e != nil {
+		return nil, fmt.Errorf("who am i error: %w", e)
+	}
+
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	return &AppIdentity{
+		Application: eb.application,
+		OrgName:     eb.orgName,
+		AppName:     eb.appName,
+		ExpiresAt:   eb.accessTokenExpiresAt,
+	}, nil
+}
+
+type RespCommon[T any] struct {
+	Action    string `json:"action"`           // 请求方法
+	Data      T      `json:"data"`             // 响应数据
+	Timestamp int64  `json:"timestamp"`        // Unix 时间戳，单位为毫秒。
+	Duration  int    `json:"duration"`         // 从发送请求到响应的时长，单位为毫秒。
+	Count     int    `json:"count,omitempty"`  // 返回的数据条数
+	Cursor    string `json:"cursor,omitempty"` // 分页游标
+}
+
+// 推送策略，即 PushReqCommon 的 Strategy 字段
+const (
+	PushStrategyAll             = 0 // 同时通过第三方推送厂商和环信推送
+	PushStrategyThirdPartyFirst = 1 // 第三方推送厂商优先，失败时通过环信推送
+	PushStrategyEasemobFirst    = 2 // 环信推送优先，失败时通过第三方推送厂商推送
+	PushStrategyThirdPartyOnly  = 3 // 仅通过第三方推送厂商推送
+	PushStrategyEasemobOnly     = 4 // 仅通过环信推送
+)
+
+// validatePushStrategy 校验推送策略是否在 [PushStrategyAll, PushStrategyEasemobOnly] 范围内
+func validatePushStrategy(strategy int) error {
+	if strategy < PushStrategyAll || strategy > PushStrategyEasemobOnly {
+		return fmt.Errorf("invalid push strategy %d", strategy)
+	}
+
+	return nil
+}
+
 type PushReqCommon struct {
 	Targets     []string     `json:"targets,omitempty"` // 推送的目标用户 ID。最多可传 100 个。
-	Strategy    int          `json:"strategy"`          // 推送策略: 0-4 具体参考: https://doc.easemob.com/push/push_send_notification.html#http-%E8%AF%B7%E6%B1%82
+	Strategy    int          `json:"strategy"`          // 推送策略: PushStrategyAll 等 0-4 具体参考: https://doc.easemob.com/push/push_send_notification.html#http-%E8%AF%B7%E6%B1%82
 	PushMessage *PushMessage `json:"pushMessage"`       // 推送通知。关于通知内容，请查看 https://doc.easemob.com/push/push_notification_config.html
 }
 
 type PushMessage struct {
 	Title   string      `json:"title"`            // 通知栏展示的通知标题，默认为“您有一条新消息”。该字段长度不能超过 32 个字符（一个汉字相当于两个字符）。
 	Content string      `json:"content"`          // 通知栏展示的通知内容。默认为“请及时查看”。该字段长度不能超过 100 个字符（一个汉字相当于两个字符）。
-	Ext     interface{} `json:"ext,omitempty"`    // 推送自定义扩展信息，为自定义 key-value 键值对。键值对个数不能超过 10 且长度不能超过 1024 个字符。
+	Ext     interface{} `json:"ext,omitempty"`    // 推送自定义扩展信息 (可以使用 Ext)，为自定义 key-value 键值对。键值对个数不能超过 10 且长度不能超过 1024 个字符。
 	Config  *PushConfig `json:"config,omitempty"` // 与用户点击通知相关的操作。以及角标的配置，包含 clickAction 和 badge 字段。
 }
 
+// 推送通知的长度限制
+const (
+	PushTitleMaxLen   = 32   // 标题的最大长度，一个汉字 (非 ASCII 字符) 相当于两个字符
+	PushContentMaxLen = 100  // 内容的最大长度，一个汉字 (非 ASCII 字符) 相当于两个字符
+	PushExtMaxKeys    = 10   // 扩展信息的最大键值对个数
+	PushExtMaxBytes   = 1024 // 扩展信息序列化为 JSON 后的最大长度
+)
+
+// pushTextLen 按照推送通知的规则计算长度，一个汉字 (非 ASCII 字符) 相当于两个字符
+func pushTextLen(text string) int {
+	n := 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			n++
+		} else {
+			n += 2
+		}
+	}
+
+	return n
+}
+
+// Validate 校验推送通知的长度限制，PushSync 和 PushSingle 发送前会自动调用
+func (m *PushMessage) Validate() error {
+	if m == nil {
+		return errors.New("push message is nil")
+	}
+
+	if pushTextLen(m.Title) > PushTitleMaxLen {
+		return fmt.Errorf("push message title length > %d", PushTitleMaxLen)
+	}
+
+	if pushTextLen(m.Content) > PushContentMaxLen {
+		return fmt.Errorf("push message content length > %d", PushContentMaxLen)
+	}
+
+	if m.Ext == nil {
+		return nil
+	}
+
+	data, e := json.Marshal(m.Ext)
+	if e != nil {
+		return fmt.Errorf("push message ext error: %w", e)
+	}
+
+	if len(data) > PushExtMaxBytes {
+		return fmt.Errorf("push message ext length > %d bytes", PushExtMaxBytes)
+	}
+
+	ext := map[string]json.RawMessage{}
+	if e := json.Unmarshal(data, &ext); e != nil {
+		return errors.New("push message ext must be a key-value object")
+	}
+
+	if len(ext) > PushExtMaxKeys {
+		return fmt.Errorf("push message ext keys > %d", PushExtMaxKeys)
+	}
+
+	return nil
+}
+
 type PushConfig struct {
 	ClickAction *PushConfigClickAction `json:"clickAction,omitempty"` // 在通知栏中点击触发的动作
 	Badge       *PushConfigBadge       `json:"badge,omitempty"`       // 推送角标
@@ -117,25 +266,44 @@ type PushSyncRespStatusData struct {
 // 调用该接口以同步方式推送消息时，环信或第三方推送厂商在推送消息后，会将推送结果发送给环信服务器。服务器根据收到的推送结果判断推送状态。 该接口调用频率默认为 1 次/秒
 // strategy: 推送策略, target: 推送目标，msg: 推送消息
 func (em *Easemob) PushSync(ctx context.Context, strategy int, target string, msg *PushMessage) (*PushRespCommon[PushSyncRespData], error) {
+	return em.PushSyncMulti(ctx, strategy, []string{target}, msg)
+}
+
+// 以同步方式为多个用户发送推送通知
+// 与 PushSync 相同，推送目标通过请求体的 targets 传递，最多 PushSingleMaxTargets 个，任一目标推送失败时返回错误
+// strategy: 推送策略, targets: 推送目标，msg: 推送消息
+func (em *Easemob) PushSyncMulti(ctx context.Context, strategy int, targets []string, msg *PushMessage) (*PushRespCommon[PushSyncRespData], error) {
+	if e := validatePushStrategy(strategy); e != nil {
+		return nil, fmt.Errorf("push sync error: %w", e)
+	}
+
+	if e := validatePushTargets(targets); e != nil {
+		return nil, fmt.Errorf("push sync error: %w", e)
+	}
+
+	if e := msg.Validate(); e != nil {
+		return nil, fmt.Errorf("push sync error: %w", e)
+	}
+
 	c, e := em.GetAccessClient(ctx)
 	if e != nil {
 		return nil, fmt.Errorf("get client error: %w", e)
 	}
 
-	res, e := c.Post(em.GetURL(path.Join("push/sync", target)).String()).
+	res, e := em.end(c.Post(em.GetPushSyncURL("")).
 		Set(ureq.ContentType, "application/json").
 		Set(ureq.Accept, "application/json").
 		Send(&PushReqCommon{
+			Targets:     targets,
 			Strategy:    strategy,
 			PushMessage: msg,
-		}).End()
+		}))
 	if e != nil {
 		return nil, fmt.Errorf("push sync error: %w", e)
 	}
 
 	if !res.OK() {
-		text, _ := res.Text()
-		return nil, fmt.Errorf("push sync error: %s, %s", res.Status, text)
+		return nil, newResponseError("push sync", res)
 	}
 
 	resp := &PushRespCommon[PushSyncRespData]{}
@@ -152,6 +320,28 @@ func (em *Easemob) PushSync(ctx context.Context, strategy int, target string, ms
 	return resp, nil
 }
 
+// PushSingleMaxTargets PushSingle 和 PushSyncMulti 单次请求的最大推送目标数
+const PushSingleMaxTargets = 100
+
+// validatePushTargets 校验推送目标: 不能为空, 不能超过 PushSingleMaxTargets 个, 不能包含空用户
+func validatePushTargets(targets []string) error {
+	if len(targets) < 1 {
+		return errors.New("targets must not be empty")
+	}
+
+	if len(targets) > PushSingleMaxTargets {
+		return fmt.Errorf("targets length > %d", PushSingleMaxTargets)
+	}
+
+	for _, v := range targets {
+		if len(strings.TrimSpace(v)) < 1 {
+			return errors.New("targets must not contain empty username")
+		}
+	}
+
+	return nil
+}
+
 type PushSingleRespData struct {
 	PushStatus string `json:"pushStatus"` // 推送状态：ASYNC_SUCCESS 表示推送成功。
 	Data       string `json:"data"`       // 异步推送的结果，即成功或失败。
@@ -162,36 +352,245 @@ type PushSingleRespData struct {
 // 调用该接口以异步方式为指定的单个或多个用户进行消息推送。
 // strategy: 推送策略, targets: 推送目标，msg: 推送消息
 func (em *Easemob) PushSingle(ctx context.Context, strategy int, targets []string, msg *PushMessage) (*PushRespCommon[PushSingleRespData], error) {
+	if e := validatePushStrategy(strategy); e != nil {
+		return nil, fmt.Errorf("push single error: %w", e)
+	}
+
+	if e := validatePushTargets(targets); e != nil {
+		return nil, fmt.Errorf("push single error: %w", e)
+	}
+
+	if e := msg.Validate(); e != nil {
+		return nil, fmt.Errorf("push single error: %w", e)
+	}
+
 	c, e := em.GetAccessClient(ctx)
 	if e != nil {
 		return nil, fmt.Errorf("get client error: %w", e)
 	}
 
-	if len(targets) > 100 {
-		return nil, errors.New("push single error: targets length > 100")
-	}
-
-	res, e := c.Post(em.GetURL("push/single").String()).
+	res, e := em.end(c.Post(em.GetPushSingleURL()).
 		Set(ureq.ContentType, "application/json").
 		Set(ureq.Accept, "application/json").
 		Send(&PushReqCommon{
 			Targets:     targets,
 			Strategy:    strategy,
 			PushMessage: msg,
-		}).End()
+		}))
 	if e != nil {
-		return nil, fmt.Errorf("push sync error: %w", e)
+		return nil, fmt.Errorf("push single error: %w", e)
 	}
 
 	if !res.OK() {
-		text, _ := res.Text()
-		return nil, fmt.Errorf("push sync error: %s, %s", res.Status, text)
+		return nil, newResponseError("push single", res)
 	}
 
 	resp := &PushRespCommon[PushSingleRespData]{}
 	if e = res.JSON(resp); e != nil {
-		return nil, fmt.Errorf("push sync error: %w", e)
+		return nil, fmt.Errorf("push single error: %w", e)
 	}
 
 	return resp, nil
 }
+
+// PushSingleAll 不限目标数量的 PushSingle, 每 PushSingleMaxTargets 个目标为一批并发请求 (并发数见 SetBatchConcurrency)
+// 返回的结果与批次顺序一致, 失败批次对应位置为 nil, 所有失败会合并到返回的错误中;
+// 某一批次出现不可重试的错误 (除限流, 服务端错误和网络错误以外) 时, 取消尚未发出的批次, 这些批次记录取消原因
+// strategy: 推送策略, targets: 推送目标, msg: 推送消息
+func (em *Easemob) PushSingleAll(ctx context.Context, strategy int, targets []string, msg *PushMessage) ([]*PushRespCommon[PushSingleRespData], error) {
+	if len(targets) < 1 {
+		return nil, errors.New("push single all error: targets is empty")
+	}
+
+	if e := validatePushStrategy(strategy); e != nil {
+		return nil, fmt.Errorf("push single all error: %w", e)
+	}
+
+	if e := msg.Validate(); e != nil {
+		return nil, fmt.Errorf("push single all error: %w", e)
+	}
+
+	em.mu.RLock()
+	concurrency := em.batchConcurrency
+	em.mu.RUnlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, max(concurrency, 1))
+		results = make([]*PushRespCommon[PushSingleRespData], (len(targets)+PushSingleMaxTargets-1)/PushSingleMaxTargets)
+		errs    = []error{}
+	)
+
+	for i := range results {
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+
+		// 已取消时剩余批次不再发出, 记录取消原因
+		if e := ctx.Err(); e != nil {
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("batch %d: %w", i, e))
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			start := i * PushSingleMaxTargets
+			resp, e := em.PushSingle(ctx, strategy, targets[start:min(start+PushSingleMaxTargets, len(targets))], msg)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if e != nil {
+				errs = append(errs, fmt.Errorf("batch %d: %w", i, e))
+				if !isRetryable(e) {
+					cancel()
+				}
+				return
+			}
+
+			results[i] = resp
+		}(i)
+	}
+
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
+
+// isRetryable 错误是否可以重试: 限流, 服务端错误和网络错误, 参数校验等其他错误都不可重试
+func isRetryable(e error) bool {
+	if errors.Is(e, context.Canceled) || errors.Is(e, context.DeadlineExceeded) {
+		return false
+	}
+
+	re := &ResponseError{}
+	if errors.As(e, &re) {
+		return re.StatusCode == http.StatusTooManyRequests || re.StatusCode >= http.StatusInternalServerError
+	}
+
+	var ne net.Error
+	return errors.As(e, &ne)
+}
+
+type PushSingleStatusResp struct {
+	TaskId       string            `json:"taskId"`       // 推送任务 ID
+	Status       string            `json:"status"`       // 任务状态，例如 PROCESSING、FINISHED。
+	SuccessCount int               `json:"successCount"` // 推送成功的用户数
+	FailCount    int               `json:"failCount"`    // 推送失败的用户数
+	Users        map[string]string `json:"users"`        // 每个用户的推送结果，key 为用户 ID，value 为 SUCCESS 或失败原因。
+}
+
+// 查询异步推送的结果
+// PushSingle 返回 ASYNC_SUCCESS 只表示任务已提交，调用该接口查询推送是否真正送达设备。
+// taskId: 推送任务 ID
+func (em *Easemob) GetPushSingleStatus(ctx context.Context, taskId string) (*PushSingleStatusResp, error) {
+	if len(taskId) < 1 {
+		return nil, errors.New("get push single status error: task id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("push/status", taskId)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get push single status error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get push single status", res)
+	}
+
+	resp := &RespCommon[*PushSingleStatusResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get push single status error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return nil, errors.New("get push single status error: empty data")
+	}
+
+	if len(resp.Data.TaskId) < 1 {
+		resp.Data.TaskId = taskId
+	}
+
+	return resp.Data, nil
+}
+
+type CertificateInfo struct {
+	Name        string    // 证书名称，即客户端 SDK 中配置的推送证书名称
+	Type        string    // 证书类型，例如 APNS、FCM
+	Environment string    // 证书环境，DEVELOPMENT 或 PRODUCTION
+	ExpiresAt   time.Time // 证书过期时间，没有过期时间 (例如 FCM) 时为零值
+	Fingerprint string    // 证书指纹
+}
+
+type certificateInfoResp struct {
+	Name        string `json:"name"`
+	Provider    string `json:"provider"`
+	Environment string `json:"environment"`
+	ExpiresAt   int64  `json:"expiresAt"` // Unix 时间戳，单位为毫秒
+	Fingerprint string `json:"fingerprint"`
+}
+
+type certificatesResp struct {
+	Entities []*certificateInfoResp `json:"entities"`
+}
+
+// 获取 App 上传的推送证书
+// 可以根据 ExpiresAt 在证书过期前提醒更新，证书过期后对应通道的推送会静默失败。
+func (em *Easemob) GetAppCertificates(ctx context.Context) ([]*CertificateInfo, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL("notifiers").String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get app certificates error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get app certificates", res)
+	}
+
+	resp := &certificatesResp{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get app certificates error: %w", e)
+	}
+
+	certs := make([]*CertificateInfo, 0, len(resp.Entities))
+	for _, v := range resp.Entities {
+		cert := &CertificateInfo{
+			Name:        v.Name,
+			Type:        v.Provider,
+			Environment: v.Environment,
+			Fingerprint: v.Fingerprint,
+		}
+
+		if v.ExpiresAt > 0 {
+			cert.ExpiresAt = time.UnixMilli(v.ExpiresAt)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	return certs, nil
+}
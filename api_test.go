@@ -0,0 +1,352 @@
+package easemob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenHandler 模拟 Token 接口, 只接受 secret 作为 client_secret
+func tokenHandler(t *testing.T, secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &refreshTokenReq{}
+		decodeBody(t, r, req)
+
+		if req.GrantType != "client_credentials" || req.ClientId != "client-id" {
+			t.Errorf("unexpected token request: %+v", req)
+		}
+
+		if req.ClientSecret != secret {
+			writeError(w, http.StatusUnauthorized, "invalid_grant", "client_secret is invalid")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"application":  "app-uuid",
+			"access_token": "new-token",
+			"expires_in":   3600,
+		})
+	}
+}
+
+func TestWhoAmI(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/token", tokenHandler(t, "client-secret"))
+
+	em := newTestEasemob(t, mux)
+
+	id, e := em.WhoAmI(context.Background())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if id.Application != "app-uuid" || id.OrgName != "org" || id.AppName != "app" {
+		t.Errorf("unexpected identity: %+v", id)
+	}
+
+	if d := time.Until(id.ExpiresAt); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("unexpected expiry: %s", id.ExpiresAt)
+	}
+}
+
+func TestWhoAmIInvalidCredentials(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/token", tokenHandler(t, "another-secret"))
+
+	em := newTestEasemob(t, mux)
+
+	_, e := em.WhoAmI(context.Background())
+	if !errors.Is(e, ErrUnauthorized) {
+		t.Fatalf("expected unauthorized error, got %v", e)
+	}
+}
+
+func TestRotateClientSecret(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/token", tokenHandler(t, "new-secret"))
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	// 新的 client_secret 无效时恢复为原来的 client_secret, 已有的 Token 不受影响
+	if e := em.RotateClientSecret(ctx, "bad-secret"); !errors.Is(e, ErrUnauthorized) {
+		t.Fatalf("expected unauthorized error, got %v", e)
+	}
+
+	em.mu.RLock()
+	secret, token := em.clientSecret, em.accessToken
+	em.mu.RUnlock()
+
+	if secret != "client-secret" || token != "test-token" {
+		t.Errorf("unexpected state after failed rotation: secret %q, token %q", secret, token)
+	}
+
+	if e := em.RotateClientSecret(ctx, "new-secret"); e != nil {
+		t.Fatal(e)
+	}
+
+	em.mu.RLock()
+	secret, token = em.clientSecret, em.accessToken
+	em.mu.RUnlock()
+
+	if secret != "new-secret" || token != "new-token" {
+		t.Errorf("unexpected state after rotation: secret %q, token %q", secret, token)
+	}
+
+	// 之后刷新 Token 使用新的 client_secret
+	if _, e := em.WhoAmI(ctx); e != nil {
+		t.Fatal(e)
+	}
+
+	if e := em.RotateClientSecret(ctx, " "); e == nil {
+		t.Error("expected error for empty secret")
+	}
+}
+
+// pushSingleServer 模拟异步推送接口, fail 中的用户所在批次返回 status 错误
+type pushSingleServer struct {
+	mu      sync.Mutex
+	batches [][]string
+	fail    map[string]int
+}
+
+func (s *pushSingleServer) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/push/single", func(w http.ResponseWriter, r *http.Request) {
+		req := &PushReqCommon{}
+		decodeBody(t, r, req)
+
+		s.mu.Lock()
+		s.batches = append(s.batches, req.Targets)
+		s.mu.Unlock()
+
+		for _, v := range req.Targets {
+			if status, ok := s.fail[v]; ok {
+				writeError(w, status, "push_error", "push failed")
+				return
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]string{{"pushStatus": "ASYNC_SUCCESS", "data": "succeed", "desc": req.Targets[0]}},
+		})
+	})
+
+	return mux
+}
+
+// pushTargets 生成 n 个推送目标 u0, u1, ...
+func pushTargets(n int) []string {
+	targets := make([]string, n)
+	for i := range targets {
+		targets[i] = "u" + strconv.Itoa(i)
+	}
+
+	return targets
+}
+
+func TestPushSingleAll(t *testing.T) {
+	s := &pushSingleServer{fail: map[string]int{"u150": http.StatusInternalServerError}}
+	em := newTestEasemob(t, s.handler(t))
+	em.SetBatchConcurrency(1)
+
+	msg := &PushMessage{Title: "title", Content: "content"}
+	results, e := em.PushSingleAll(context.Background(), PushStrategyEasemobOnly, pushTargets(250), msg)
+
+	// 服务端错误可以重试, 不会取消其他批次
+	re := &ResponseError{}
+	if !errors.As(e, &re) || re.StatusCode != http.StatusInternalServerError || !strings.Contains(e.Error(), "batch 1:") {
+		t.Errorf("expected batch 1 to fail, got %v", e)
+	}
+
+	if len(s.batches) != 3 || len(s.batches[0]) != 100 || len(s.batches[2]) != 50 {
+		t.Fatalf("unexpected batches: %d", len(s.batches))
+	}
+
+	if len(results) != 3 || results[1] != nil || results[0].Data[0].Desc != "u0" || results[2].Data[0].Desc != "u200" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestPushSingleAllConcurrent(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ids = map[string]int{}
+		seq atomic.Int64
+	)
+
+	s := &pushSingleServer{}
+	h := s.handler(t)
+
+	em := newTestEasemob(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		for _, v := range r.Header.Values("X-Request-Id") {
+			ids[v]++
+		}
+		mu.Unlock()
+
+		if v := r.Header.Values("X-Request-Id"); len(v) != 1 || strings.Contains(v[0], ",") {
+			t.Errorf("unexpected request id: %v", v)
+		}
+
+		h.ServeHTTP(w, r)
+	}))
+	em.SetBatchConcurrency(4)
+
+	// 每个请求使用独立的客户端, 并发批次的请求头不会互相覆盖
+	em.SetRequestHook(func(r *http.Request) error {
+		r.Header.Set("X-Request-ID", "req-"+strconv.FormatInt(seq.Add(1), 10))
+		return nil
+	})
+
+	msg := &PushMessage{Title: "title", Content: "content"}
+	results, e := em.PushSingleAll(context.Background(), PushStrategyEasemobOnly, pushTargets(1000), msg)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if len(results) != 10 || len(s.batches) != 10 {
+		t.Fatalf("unexpected batches: %d results, %d requests", len(results), len(s.batches))
+	}
+
+	for i, v := range results {
+		if v == nil || v.Data[0].Desc != "u"+strconv.Itoa(i*PushSingleMaxTargets) {
+			t.Errorf("unexpected result of batch %d: %+v", i, v)
+		}
+	}
+
+	if len(ids) != 10 {
+		t.Errorf("request ids are shared between batches: %v", ids)
+	}
+}
+
+func TestPushSingleAllCancel(t *testing.T) {
+	s := &pushSingleServer{}
+	em := newTestEasemob(t, s.handler(t))
+	em.SetBatchConcurrency(1)
+
+	// 第一批包含空用户, 参数校验失败后剩余批次不再发出
+	targets := pushTargets(250)
+	targets[3] = ""
+
+	msg := &PushMessage{Title: "title", Content: "content"}
+	results, e := em.PushSingleAll(context.Background(), PushStrategyEasemobOnly, targets, msg)
+	if e == nil {
+		t.Fatal("expected error")
+	}
+
+	if len(s.batches) != 0 {
+		t.Errorf("canceled batches were sent: %d", len(s.batches))
+	}
+
+	for i := range results {
+		if results[i] != nil {
+			t.Errorf("unexpected result of batch %d: %+v", i, results[i])
+		}
+	}
+
+	for _, v := range []string{"batch 1: context canceled", "batch 2: context canceled"} {
+		if !strings.Contains(e.Error(), v) {
+			t.Errorf("error does not mention %q: %v", v, e)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, v := range []struct {
+		e    error
+		want bool
+	}{
+		{&ResponseError{StatusCode: http.StatusTooManyRequests}, true},
+		{&ResponseError{StatusCode: http.StatusBadGateway}, true},
+		{&ResponseError{StatusCode: http.StatusBadRequest}, false},
+		{fmt.Errorf("push single error: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), true},
+		{errors.New("push single error: targets must not contain empty username"), false},
+		{context.Canceled, false},
+	} {
+		if got := isRetryable(v.e); got != v.want {
+			t.Errorf("isRetryable(%v) = %v, want %v", v.e, got, v.want)
+		}
+	}
+}
+
+func TestPushSyncMulti(t *testing.T) {
+	var got [][]string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/push/sync", func(w http.ResponseWriter, r *http.Request) {
+		req := &PushReqCommon{}
+		decodeBody(t, r, req)
+		got = append(got, req.Targets)
+
+		status := "SUCCESS"
+		if req.Targets[0] == "offline" {
+			status = "FAIL"
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]interface{}{{"pushStatus": status}},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+	msg := &PushMessage{Title: "title", Content: "content"}
+
+	if _, e := em.PushSyncMulti(ctx, PushStrategyAll, []string{"alice", "bob"}, msg); e != nil {
+		t.Fatal(e)
+	}
+
+	if _, e := em.PushSync(ctx, PushStrategyAll, "offline", msg); e == nil {
+		t.Error("expected error for failed push")
+	}
+
+	if !reflect.DeepEqual(got, [][]string{{"alice", "bob"}, {"offline"}}) {
+		t.Errorf("unexpected targets: %v", got)
+	}
+
+	// 无效的推送目标不会发出请求
+	if _, e := em.PushSync(ctx, PushStrategyAll, "", msg); e == nil {
+		t.Error("expected error for empty target")
+	}
+
+	if _, e := em.PushSyncMulti(ctx, PushStrategyAll, []string{"alice", " "}, msg); e == nil {
+		t.Error("expected error for blank target")
+	}
+
+	if _, e := em.PushSyncMulti(ctx, PushStrategyAll, pushTargets(PushSingleMaxTargets+1), msg); e == nil {
+		t.Error("expected error for too many targets")
+	}
+
+	if len(got) != 2 {
+		t.Errorf("invalid targets should not be sent: %v", got[2:])
+	}
+}
+
+func TestPushStrategy(t *testing.T) {
+	data, e := json.Marshal(&PushReqCommon{Targets: []string{"alice"}, Strategy: PushStrategyEasemobOnly})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, data, `{"targets": ["alice"], "strategy": 4, "pushMessage": null}`)
+
+	em := newTestEasemob(t, failHandler(t))
+	msg := &PushMessage{Title: "title", Content: "content"}
+
+	for _, v := range []int{-1, PushStrategyEasemobOnly + 1} {
+		if _, e := em.PushSingle(context.Background(), v, []string{"alice"}, msg); e == nil {
+			t.Errorf("expected error for strategy %d", v)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"uw/ureq"
+)
+
+type RoomAttrResult struct {
+	SuccessKeys []string          `json:"successKeys"` // 操作成功的属性名
+	ErrorKeys   map[string]string `json:"errorKeys"`   // 操作失败的属性名及原因
+}
+
+type roomUserMetadataReq struct {
+	MetaData   map[string]string `json:"metaData,omitempty"`
+	Keys       []string          `json:"keys,omitempty"`
+	AutoDelete string            `json:"autoDelete,omitempty"` // DELETE: 用户退出聊天室时删除属性, NO_DELETE: 不删除
+}
+
+type roomUserMetadataGetReq struct {
+	Targets    []string `json:"targets"`
+	Properties []string `json:"properties,omitempty"`
+}
+
+// SetRoomUserAttributes 设置聊天室成员的自定义属性 (例如直播间观众角色)
+// roomId: 聊天室 ID, caller: 聊天室成员 ID, attrs: 属性键值对, autoDelete: 成员退出聊天室时是否删除其属性
+func (em *Easemob) SetRoomUserAttributes(ctx context.Context, roomId, caller string, attrs map[string]string, autoDelete bool) (*RoomAttrResult, error) {
+	if len(roomId) < 1 || len(caller) < 1 {
+		return nil, errors.New("set room user attributes error: room id or caller is empty")
+	}
+
+	if len(attrs) < 1 {
+		return nil, errors.New("set room user attributes error: attrs is empty")
+	}
+
+	req := &roomUserMetadataReq{
+		MetaData:   attrs,
+		AutoDelete: "NO_DELETE",
+	}
+
+	if autoDelete {
+		req.AutoDelete = "DELETE"
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Put(em.GetURL(path.Join("metadata/chatroom", roomId, "user", caller)).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(req))
+	if e != nil {
+		return nil, fmt.Errorf("set room user attributes error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("set room user attributes", res)
+	}
+
+	resp := &RespCommon[*RoomAttrResult]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("set room user attributes error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return &RoomAttrResult{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+// GetRoomUserAttributes 获取聊天室成员的自定义属性, 返回以成员 ID 为键的属性表
+// roomId: 聊天室 ID, usernames: 成员 ID, keys: 属性名, 为空时返回全部属性
+func (em *Easemob) GetRoomUserAttributes(ctx context.Context, roomId string, usernames, keys []string) (map[string]map[string]string, error) {
+	if len(roomId) < 1 || len(usernames) < 1 {
+		return nil, errors.New("get room user attributes error: room id or usernames is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("metadata/chatroom", roomId, "get")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&roomUserMetadataGetReq{
+			Targets:    usernames,
+			Properties: keys,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("get room user attributes error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get room user attributes", res)
+	}
+
+	resp := &RespCommon[map[string]map[string]string]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get room user attributes error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return map[string]map[string]string{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+// DeleteRoomUserAttributes 删除聊天室成员的自定义属性
+// roomId: 聊天室 ID, caller: 聊天室成员 ID, keys: 要删除的属性名
+func (em *Easemob) DeleteRoomUserAttributes(ctx context.Context, roomId, caller string, keys []string) (*RoomAttrResult, error) {
+	if len(roomId) < 1 || len(caller) < 1 {
+		return nil, errors.New("delete room user attributes error: room id or caller is empty")
+	}
+
+	if len(keys) < 1 {
+		return nil, errors.New("delete room user attributes error: keys is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("metadata/chatroom", roomId, "user", caller)).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&roomUserMetadataReq{
+			Keys: keys,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("delete room user attributes error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("delete room user attributes", res)
+	}
+
+	resp := &RespCommon[*RoomAttrResult]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("delete room user attributes error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return &RoomAttrResult{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+type roomUserCountResp struct {
+	Id    string `json:"id"`    // 聊天室 ID
+	Count int64  `json:"count"` // 当前成员数
+}
+
+type roomsUserCountReq struct {
+	ChatroomIds []string `json:"chatroomIds"`
+}
+
+// GetRoomUserCount 获取聊天室当前成员数 (例如直播间观众数)
+// roomId: 聊天室 ID
+func (em *Easemob) GetRoomUserCount(ctx context.Context, roomId string) (int64, error) {
+	if len(roomId) < 1 {
+		return 0, errors.New("get room user count error: room id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return 0, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("chatrooms", roomId, "users/count")).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return 0, fmt.Errorf("get room user count error: %w", e)
+	}
+
+	if !res.OK() {
+		return 0, newResponseError("get room user count", res)
+	}
+
+	resp := &RespCommon[*roomUserCountResp]{}
+	if e = res.JSON(resp); e != nil {
+		return 0, fmt.Errorf("get room user count error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return 0, nil
+	}
+
+	return resp.Data.Count, nil
+}
+
+// GetRoomsUserCount 批量获取聊天室当前成员数, 返回以聊天室 ID 为键的成员数
+// roomIds: 聊天室 ID 列表
+func (em *Easemob) GetRoomsUserCount(ctx context.Context, roomIds []string) (map[string]int64, error) {
+	if len(roomIds) < 1 {
+		return nil, errors.New("get rooms user count error: room ids is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("chatrooms/users/count").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&roomsUserCountReq{
+			ChatroomIds: roomIds,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("get rooms user count error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get rooms user count", res)
+	}
+
+	resp := &RespCommon[[]*roomUserCountResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get rooms user count error: %w", e)
+	}
+
+	counts := make(map[string]int64, len(resp.Data))
+	for _, v := range resp.Data {
+		counts[v.Id] = v.Count
+	}
+
+	return counts, nil
+}
+
+type SendMessageResp struct {
+	Id string `json:"id"` // 消息 ID
+}
+
+type broadcastRoomReq struct {
+	From string      `json:"from,omitempty"`
+	Type string      `json:"type"`
+	Body interface{} `json:"body"`
+}
+
+// BroadcastRoomMessage 向 App 下的所有聊天室广播文本消息, 用于全平台公告
+// 注意: 该接口默认调用频率为 1 次/分钟, 超过后返回的错误满足 errors.Is(e, ErrTooManyRequests)
+// from: 发送方, 为空时为 admin, msg: 消息内容
+func (em *Easemob) BroadcastRoomMessage(ctx context.Context, from string, msg *TextBody) (*SendMessageResp, error) {
+	if msg == nil || len(msg.Msg) < 1 {
+		return nil, errors.New("broadcast room message error: msg is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("chatrooms/chatroommsgbroadcast").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&broadcastRoomReq{
+			From: from,
+			Type: "txt",
+			Body: msg,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("broadcast room message error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("broadcast room message", res)
+	}
+
+	resp := &RespCommon[*SendMessageResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("broadcast room message error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return &SendMessageResp{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+type roomSuperAdminReq struct {
+	SuperAdmin string `json:"superadmin"`
+}
+
+// GetRoomSuperAdmins 分页获取聊天室超级管理员, 超级管理员可以创建聊天室并管理所有聊天室
+// pageNum: 页码 (从 1 开始), pageSize: 每页数量
+func (em *Easemob) GetRoomSuperAdmins(ctx context.Context, pageNum, pageSize int) ([]string, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL("chatrooms/super_admin")
+	q := url.Values{}
+	if pageNum > 0 {
+		q.Set("pagenum", strconv.Itoa(pageNum))
+	}
+	if pageSize > 0 {
+		q.Set("pagesize", strconv.Itoa(pageSize))
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get room super admins error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get room super admins", res)
+	}
+
+	resp := &RespCommon[[]string]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get room super admins error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return []string{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+// SetRoomSuperAdmin 将用户设置为聊天室超级管理员
+// username: 用户 ID
+func (em *Easemob) SetRoomSuperAdmin(ctx context.Context, username string) error {
+	if len(username) < 1 {
+		return errors.New("set room super admin error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("chatrooms/super_admin").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&roomSuperAdminReq{
+			SuperAdmin: username,
+		}))
+	if e != nil {
+		return fmt.Errorf("set room super admin error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("set room super admin", res)
+	}
+
+	return nil
+}
+
+// RemoveRoomSuperAdmin 撤销用户的聊天室超级管理员权限
+// username: 用户 ID
+func (em *Easemob) RemoveRoomSuperAdmin(ctx context.Context, username string) error {
+	if len(username) < 1 {
+		return errors.New("remove room super admin error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("chatrooms/super_admin", username)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("remove room super admin error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("remove room super admin", res)
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRoomUserAttributes(t *testing.T) {
+	var putBody, getBody, deleteBody []byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /org/app/metadata/chatroom/room1/user/alice", func(w http.ResponseWriter, r *http.Request) {
+		putBody, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"successKeys": ["role"], "errorKeys": {"level": "value is too long"}}}`))
+	})
+	mux.HandleFunc("POST /org/app/metadata/chatroom/room1/get", func(w http.ResponseWriter, r *http.Request) {
+		getBody, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"alice": {"role": "host"}, "bob": {"role": "audience"}}}`))
+	})
+	mux.HandleFunc("DELETE /org/app/metadata/chatroom/room1/user/alice", func(w http.ResponseWriter, r *http.Request) {
+		deleteBody, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"successKeys": ["role"]}}`))
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	result, e := em.SetRoomUserAttributes(ctx, "room1", "alice", map[string]string{"role": "host"}, true)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, putBody, `{"metaData": {"role": "host"}, "autoDelete": "DELETE"}`)
+	if !reflect.DeepEqual(result, &RoomAttrResult{
+		SuccessKeys: []string{"role"},
+		ErrorKeys:   map[string]string{"level": "value is too long"},
+	}) {
+		t.Errorf("unexpected set result: %+v", result)
+	}
+
+	if _, e := em.SetRoomUserAttributes(ctx, "room1", "alice", map[string]string{"role": "host"}, false); e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, putBody, `{"metaData": {"role": "host"}, "autoDelete": "NO_DELETE"}`)
+
+	attrs, e := em.GetRoomUserAttributes(ctx, "room1", []string{"alice", "bob"}, []string{"role"})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, getBody, `{"targets": ["alice", "bob"], "properties": ["role"]}`)
+	if !reflect.DeepEqual(attrs, map[string]map[string]string{
+		"alice": {"role": "host"},
+		"bob":   {"role": "audience"},
+	}) {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+
+	if _, e := em.GetRoomUserAttributes(ctx, "room1", []string{"alice"}, nil); e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, getBody, `{"targets": ["alice"]}`)
+
+	result, e = em.DeleteRoomUserAttributes(ctx, "room1", "alice", []string{"role"})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, deleteBody, `{"keys": ["role"]}`)
+	if !reflect.DeepEqual(result.SuccessKeys, []string{"role"}) {
+		t.Errorf("unexpected delete result: %+v", result)
+	}
+}
+
+func TestRoomUserAttributesValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	ctx := context.Background()
+
+	if _, e := em.SetRoomUserAttributes(ctx, "room1", "alice", nil, false); e == nil {
+		t.Error("expected error for empty attrs")
+	}
+
+	if _, e := em.GetRoomUserAttributes(ctx, "", []string{"alice"}, nil); e == nil {
+		t.Error("expected error for empty room id")
+	}
+
+	if _, e := em.DeleteRoomUserAttributes(ctx, "room1", "alice", nil); e == nil {
+		t.Error("expected error for empty keys")
+	}
+}
+
+func TestRoomUserCount(t *testing.T) {
+	var batchBody []byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatrooms/room1/users/count", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"id": "room1", "count": 1024}}`))
+	})
+	mux.HandleFunc("POST /org/app/chatrooms/users/count", func(w http.ResponseWriter, r *http.Request) {
+		batchBody, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [{"id": "room1", "count": 1024}, {"id": "room2", "count": 0}]}`))
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	count, e := em.GetRoomUserCount(ctx, "room1")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if count != 1024 {
+		t.Errorf("unexpected count: %d", count)
+	}
+
+	counts, e := em.GetRoomsUserCount(ctx, []string{"room1", "room2"})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, batchBody, `{"chatroomIds": ["room1", "room2"]}`)
+	if !reflect.DeepEqual(counts, map[string]int64{"room1": 1024, "room2": 0}) {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+
+	if _, e := em.GetRoomsUserCount(ctx, nil); e == nil {
+		t.Error("expected error for empty room ids")
+	}
+}
+
+func TestBroadcastRoomMessage(t *testing.T) {
+	var body []byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/chatrooms/chatroommsgbroadcast", func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"id": "1176424385384353793"}}`))
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	resp, e := em.BroadcastRoomMessage(ctx, "system", &TextBody{Msg: "直播即将开始"})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, body, `{"from": "system", "type": "txt", "body": {"msg": "直播即将开始"}}`)
+	if resp.Id != "1176424385384353793" {
+		t.Errorf("unexpected message id: %s", resp.Id)
+	}
+
+	if _, e := em.BroadcastRoomMessage(ctx, "", &TextBody{}); e == nil {
+		t.Error("expected error for empty msg")
+	}
+}
+
+func TestBroadcastRoomMessageRateLimited(t *testing.T) {
+	em := newTestEasemob(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusTooManyRequests, "resource_limited", "too many requests")
+	}))
+
+	if _, e := em.BroadcastRoomMessage(context.Background(), "", &TextBody{Msg: "hello"}); !errors.Is(e, ErrTooManyRequests) {
+		t.Errorf("expected ErrTooManyRequests, got %v", e)
+	}
+}
+
+func TestRoomSuperAdmins(t *testing.T) {
+	var query, removed string
+	var body []byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatrooms/super_admin", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": []string{"alice", "bob"}})
+	})
+	mux.HandleFunc("POST /org/app/chatrooms/super_admin", func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"result": "success"}})
+	})
+	mux.HandleFunc("DELETE /org/app/chatrooms/super_admin/{username}", func(w http.ResponseWriter, r *http.Request) {
+		removed = r.PathValue("username")
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"result": "success"}})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	admins, e := em.GetRoomSuperAdmins(ctx, 1, 10)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if query != "pagenum=1&pagesize=10" {
+		t.Errorf("unexpected query: %s", query)
+	}
+
+	if !reflect.DeepEqual(admins, []string{"alice", "bob"}) {
+		t.Errorf("unexpected super admins: %v", admins)
+	}
+
+	if e := em.SetRoomSuperAdmin(ctx, "carol"); e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, body, `{"superadmin": "carol"}`)
+
+	if e := em.RemoveRoomSuperAdmin(ctx, "carol"); e != nil {
+		t.Fatal(e)
+	}
+
+	if removed != "carol" {
+		t.Errorf("unexpected removed super admin: %s", removed)
+	}
+
+	if e := em.SetRoomSuperAdmin(ctx, ""); e == nil {
+		t.Error("expected error for empty username")
+	}
+}
@@ -0,0 +1,312 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"unicode/utf8"
+	"uw/ureq"
+)
+
+const (
+	ContactImportMaxBatch = 10  // 单次导入好友的最大数量
+	ContactRemarkMaxLen   = 100 // 好友备注最大长度 (字符)
+)
+
+type ContactImport struct {
+	Username string `json:"username"`         // 好友的用户 ID
+	Remark   string `json:"remark,omitempty"` // 好友备注, 可选
+}
+
+type ContactImportFail struct {
+	Username string `json:"username"` // 导入失败的用户 ID
+	Reason   string `json:"reason"`   // 失败原因
+}
+
+type ContactImportResult struct {
+	Success []string             `json:"success"` // 导入成功的用户 ID
+	Fail    []*ContactImportFail `json:"fail"`    // 导入失败的用户及原因
+}
+
+type contactImportReq struct {
+	Contacts []ContactImport `json:"contacts"`
+}
+
+type contactRemarkReq struct {
+	Remark string `json:"remark"`
+}
+
+// ImportContacts 批量导入好友 (可带备注)
+// 单次最多导入 ContactImportMaxBatch 个好友, 部分失败时不会返回错误, 失败原因见 ContactImportResult.Fail
+// owner: 用户 ID, contacts: 要导入的好友列表
+func (em *Easemob) ImportContacts(ctx context.Context, owner string, contacts []ContactImport) (*ContactImportResult, error) {
+	if len(owner) < 1 {
+		return nil, errors.New("import contacts error: owner is empty")
+	}
+
+	if len(contacts) < 1 || len(contacts) > ContactImportMaxBatch {
+		return nil, fmt.Errorf("import contacts error: contacts length must be in [1, %d]", ContactImportMaxBatch)
+	}
+
+	for _, v := range contacts {
+		if len(v.Username) < 1 {
+			return nil, errors.New("import contacts error: username is empty")
+		}
+
+		if utf8.RuneCountInString(v.Remark) > ContactRemarkMaxLen {
+			return nil, fmt.Errorf("import contacts error: remark of %s too long", v.Username)
+		}
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("user", owner, "contacts/import")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&contactImportReq{
+			Contacts: contacts,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("import contacts error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("import contacts", res)
+	}
+
+	resp := &RespCommon[*ContactImportResult]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("import contacts error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return &ContactImportResult{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+// SetContactRemark 设置好友备注
+// owner: 用户 ID, friend: 好友 ID, remark: 备注, 最长 ContactRemarkMaxLen 个字符
+func (em *Easemob) SetContactRemark(ctx context.Context, owner, friend, remark string) error {
+	if len(owner) < 1 || len(friend) < 1 {
+		return errors.New("set contact remark error: owner or friend is empty")
+	}
+
+	if utf8.RuneCountInString(remark) > ContactRemarkMaxLen {
+		return errors.New("set contact remark error: remark too long")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Put(em.GetURL(path.Join("user", owner, "contacts/users", friend)).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&contactRemarkReq{
+			Remark: remark,
+		}))
+	if e != nil {
+		return fmt.Errorf("set contact remark error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("set contact remark", res)
+	}
+
+	return nil
+}
+
+// BlockUsersMaxBatch 单次添加黑名单的最大数量
+const BlockUsersMaxBatch = 50
+
+type blockUsersReq struct {
+	Usernames []string `json:"usernames"`
+}
+
+// BlockUsers 将用户添加到黑名单
+// 重复添加已在黑名单中的用户不会报错, 单次最多 BlockUsersMaxBatch 个
+// owner: 用户 ID, blocked: 要加入黑名单的用户 ID
+func (em *Easemob) BlockUsers(ctx context.Context, owner string, blocked []string) error {
+	if len(owner) < 1 {
+		return errors.New("block users error: owner is empty")
+	}
+
+	if len(blocked) < 1 || len(blocked) > BlockUsersMaxBatch {
+		return fmt.Errorf("block users error: blocked length must be in [1, %d]", BlockUsersMaxBatch)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("users", owner, "blocks/users")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&blockUsersReq{
+			Usernames: blocked,
+		}))
+	if e != nil {
+		return fmt.Errorf("block users error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("block users", res)
+	}
+
+	return nil
+}
+
+// UnblockUser 将用户从黑名单中移除
+// owner: 用户 ID, blocked: 要移除的用户 ID
+func (em *Easemob) UnblockUser(ctx context.Context, owner, blocked string) error {
+	if len(owner) < 1 || len(blocked) < 1 {
+		return errors.New("unblock user error: owner or blocked is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("users", owner, "blocks/users", blocked)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("unblock user error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("unblock user", res)
+	}
+
+	return nil
+}
+
+// GetBlockedUsers 获取用户的黑名单列表
+// owner: 用户 ID
+func (em *Easemob) GetBlockedUsers(ctx context.Context, owner string) ([]string, error) {
+	if len(owner) < 1 {
+		return nil, errors.New("get blocked users error: owner is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("users", owner, "blocks/users")).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get blocked users error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get blocked users", res)
+	}
+
+	resp := &RespCommon[[]string]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get blocked users error: %w", e)
+	}
+
+	return resp.Data, nil
+}
+
+type Contact struct {
+	Username string `json:"username"` // 好友的用户 ID
+	Remark   string `json:"remark"`   // 好友备注, 未设置时为空
+}
+
+type ContactsPage struct {
+	Contacts []*Contact `json:"contacts"` // 好友列表
+	Cursor   string     `json:"cursor"`   // 下一页游标, 为空表示没有更多数据
+}
+
+// ListContactsPaged 分页获取好友列表 (带备注)
+// owner: 用户 ID, limit: 每页数量, cursor: 分页游标, 首页传空
+func (em *Easemob) ListContactsPaged(ctx context.Context, owner string, limit int, cursor string) (*ContactsPage, error) {
+	if len(owner) < 1 {
+		return nil, errors.New("list contacts error: owner is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if len(cursor) > 0 {
+		q.Set("cursor", cursor)
+	}
+
+	res, e := em.end(withQuery(c.Get(em.GetURL(path.Join("user", owner, "contacts")).String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("list contacts error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("list contacts", res)
+	}
+
+	resp := &RespCommon[*ContactsPage]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("list contacts error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return &ContactsPage{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+// IterateContacts 返回好友列表的分页迭代器
+// owner: 用户 ID, limit: 每页数量
+func (em *Easemob) IterateContacts(owner string, limit int) *Pager[*Contact] {
+	return newPager(func(ctx context.Context, cursor string) ([]*Contact, string, error) {
+		page, e := em.ListContactsPaged(ctx, owner, limit, cursor)
+		if e != nil {
+			return nil, "", e
+		}
+
+		return page.Contacts, page.Cursor, nil
+	})
+}
+
+// DeleteContact 删除好友, 双方的好友列表中都会移除对方
+// owner: 用户 ID, friend: 好友 ID
+func (em *Easemob) DeleteContact(ctx context.Context, owner, friend string) error {
+	if len(owner) < 1 || len(friend) < 1 {
+		return errors.New("delete contact error: owner or friend is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("users", owner, "contacts/users", friend)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("delete contact error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("delete contact", res)
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package easemob
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportContactsMixedResult(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/user/alice/contacts/import", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("unexpected authorization header: %q", r.Header.Get("Authorization"))
+		}
+
+		req := &contactImportReq{}
+		decodeBody(t, r, req)
+
+		want := []ContactImport{{Username: "bob", Remark: "同事"}, {Username: "ghost"}}
+		if !reflect.DeepEqual(req.Contacts, want) {
+			t.Errorf("unexpected contacts: %+v", req.Contacts)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"success": []string{"bob"},
+				"fail":    []map[string]string{{"username": "ghost", "reason": "user not found"}},
+			},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	result, e := em.ImportContacts(context.Background(), "alice", []ContactImport{
+		{Username: "bob", Remark: "同事"},
+		{Username: "ghost"},
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(result.Success, []string{"bob"}) {
+		t.Errorf("unexpected success: %v", result.Success)
+	}
+
+	if len(result.Fail) != 1 || result.Fail[0].Username != "ghost" || result.Fail[0].Reason != "user not found" {
+		t.Errorf("unexpected fail: %+v", result.Fail)
+	}
+}
+
+func TestImportContactsValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	// 备注按字符计算长度, 中文备注不能按字节截断
+	okRemark := strings.Repeat("备", ContactRemarkMaxLen)
+	longRemark := okRemark + "注"
+
+	tooMany := make([]ContactImport, ContactImportMaxBatch+1)
+	for i := range tooMany {
+		tooMany[i].Username = "u"
+	}
+
+	cases := map[string][]ContactImport{
+		"empty":       nil,
+		"too many":    tooMany,
+		"no username": {{Remark: "x"}},
+		"long remark": {{Username: "bob", Remark: longRemark}},
+	}
+
+	for name, contacts := range cases {
+		if _, e := em.ImportContacts(context.Background(), "alice", contacts); e == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if e := em.SetContactRemark(context.Background(), "alice", "bob", longRemark); e == nil {
+		t.Error("set contact remark: expected error for long remark")
+	}
+}
+
+func TestSetContactRemark(t *testing.T) {
+	called := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /org/app/user/alice/contacts/users/bob", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+
+		req := &contactRemarkReq{}
+		decodeBody(t, r, req)
+		if req.Remark != strings.Repeat("备", ContactRemarkMaxLen) {
+			t.Errorf("unexpected remark: %q", req.Remark)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	if e := em.SetContactRemark(context.Background(), "alice", "bob", strings.Repeat("备", ContactRemarkMaxLen)); e != nil {
+		t.Fatal(e)
+	}
+
+	if !called {
+		t.Error("remark endpoint not called")
+	}
+}
+
+func TestBlocklist(t *testing.T) {
+	blocked := map[string]bool{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/users/alice/blocks/users", func(w http.ResponseWriter, r *http.Request) {
+		req := &blockUsersReq{}
+		decodeBody(t, r, req)
+		for _, v := range req.Usernames {
+			blocked[v] = true
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": req.Usernames})
+	})
+	mux.HandleFunc("DELETE /org/app/users/alice/blocks/users/{blocked}", func(w http.ResponseWriter, r *http.Request) {
+		delete(blocked, r.PathValue("blocked"))
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+	mux.HandleFunc("GET /org/app/users/alice/blocks/users", func(w http.ResponseWriter, r *http.Request) {
+		list := []string{}
+		for _, v := range []string{"bob", "carol", "dave"} {
+			if blocked[v] {
+				list = append(list, v)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": list})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	if e := em.BlockUsers(ctx, "alice", []string{"bob", "carol", "dave"}); e != nil {
+		t.Fatal(e)
+	}
+
+	// 重复添加已在黑名单中的用户
+	if e := em.BlockUsers(ctx, "alice", []string{"bob"}); e != nil {
+		t.Fatal(e)
+	}
+
+	if e := em.UnblockUser(ctx, "alice", "carol"); e != nil {
+		t.Fatal(e)
+	}
+
+	list, e := em.GetBlockedUsers(ctx, "alice")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(list, []string{"bob", "dave"}) {
+		t.Errorf("unexpected blocked users: %v", list)
+	}
+}
+
+func TestBlockUsersOverLimit(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	if e := em.BlockUsers(context.Background(), "alice", make([]string, BlockUsersMaxBatch+1)); e == nil {
+		t.Error("expected error for too many users")
+	}
+
+	if e := em.BlockUsers(context.Background(), "alice", nil); e == nil {
+		t.Error("expected error for empty users")
+	}
+}
+
+func TestIterateContacts(t *testing.T) {
+	pages := map[string]map[string]interface{}{
+		"": {
+			"contacts": []map[string]string{{"username": "bob", "remark": "同事"}, {"username": "carol", "remark": ""}},
+			"cursor":   "c1",
+		},
+		"c1": {
+			"contacts": []map[string]string{{"username": "dave"}},
+			"cursor":   "c2",
+		},
+		"c2": {
+			"contacts": []map[string]string{{"username": "erin", "remark": "家人"}},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/user/alice/contacts", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "2" {
+			t.Errorf("unexpected limit: %q", r.URL.Query().Get("limit"))
+		}
+
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			t.Errorf("unexpected cursor: %q", r.URL.Query().Get("cursor"))
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": page})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	p := em.IterateContacts("alice", 2)
+	all, e := p.All(context.Background())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := []*Contact{
+		{Username: "bob", Remark: "同事"},
+		{Username: "carol"},
+		{Username: "dave"},
+		{Username: "erin", Remark: "家人"},
+	}
+	if !reflect.DeepEqual(all, want) {
+		t.Errorf("unexpected contacts: %+v", all)
+	}
+
+	if p.HasNext() {
+		t.Error("pager should be done after the last page")
+	}
+
+	page, e := em.ListContactsPaged(context.Background(), "alice", 2, "c1")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if page.Cursor != "c2" || len(page.Contacts) != 1 || page.Contacts[0].Username != "dave" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+}
+
+func TestIterateContactsRepeatedCursor(t *testing.T) {
+	hits := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/user/alice/contacts", func(w http.ResponseWriter, r *http.Request) {
+		// 服务端始终返回同一个游标
+		hits++
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"contacts": []map[string]string{{"username": "bob"}},
+				"cursor":   "c1",
+			},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	p := em.IterateContacts("alice", 1)
+	all, e := p.All(context.Background())
+	if e == nil {
+		t.Fatal("expected error for repeated cursor")
+	}
+
+	if hits != 2 || len(all) != 2 || p.HasNext() {
+		t.Errorf("unexpected result: %d requests, %d contacts", hits, len(all))
+	}
+}
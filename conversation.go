@@ -0,0 +1,436 @@
+package easemob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"uw/ureq"
+)
+
+type Conversation struct {
+	ChannelId   string          `json:"channel_id"`   // 会话 ID
+	Type        string          `json:"type"`         // 会话类型: chat 单聊, groupchat 群聊
+	Unread      int             `json:"unread"`       // 未读消息数
+	LastMessage json.RawMessage `json:"last_message"` // 最后一条消息, 可能为空
+	Timestamp   int64           `json:"timestamp"`    // 会话最后更新时间, Unix 时间戳, 单位为毫秒
+}
+
+type ConversationPage struct {
+	Conversations []*Conversation `json:"channel_infos"` // 会话列表
+	Cursor        string          `json:"cursor"`        // 下一页游标, 为空表示没有更多数据
+}
+
+// ConversationsPage 会话列表分页, 与 ConversationPage 相同
+type ConversationsPage = ConversationPage
+
+// Peer 从会话 ID 中解析会话对方 (用户 ID 或群组 ID) 和会话类型
+// 会话 ID 的格式为 {appkey}_{peer}@easemob.com (单聊) 或 {appkey}_{groupId}@conference.easemob.com (群聊),
+// 无法解析时返回原始的会话 ID 和 Type
+func (c *Conversation) Peer() (string, ChatType) {
+	id, chatType := c.ChannelId, ChatType(c.Type)
+	if i := strings.LastIndexByte(id, '@'); i > 0 {
+		switch id[i+1:] {
+		case "easemob.com":
+			chatType = ChatTypeChat
+		case "conference.easemob.com":
+			chatType = ChatTypeGroupChat
+		}
+		id = id[:i]
+	}
+
+	if i := strings.IndexByte(id, '_'); i > 0 && strings.Contains(id[:i], "#") {
+		id = id[i+1:]
+	}
+
+	return id, chatType
+}
+
+type LastMessageMeta struct {
+	MsgId     string          `json:"id"`        // 消息 ID
+	From      string          `json:"from"`      // 发送方
+	To        string          `json:"to"`        // 接收方
+	Timestamp int64           `json:"timestamp"` // 发送时间, Unix 时间戳, 单位为毫秒
+	Payload   json.RawMessage `json:"payload"`   // 消息内容 (bodies 和 ext)
+}
+
+// LastMessageMeta 解析会话的最后一条消息, 没有最后一条消息时返回 nil
+func (c *Conversation) LastMessageMeta() (*LastMessageMeta, error) {
+	if len(c.LastMessage) < 1 || string(c.LastMessage) == "null" {
+		return nil, nil
+	}
+
+	meta := &LastMessageMeta{}
+	if e := json.Unmarshal(c.LastMessage, meta); e != nil {
+		return nil, fmt.Errorf("decode last message error: %w", e)
+	}
+
+	return meta, nil
+}
+
+// GetUserConversations 获取用户的服务端会话列表, 包含单聊和群聊
+// username: 用户 ID, limit: 每页数量, cursor: 分页游标, 首页传空
+func (em *Easemob) GetUserConversations(ctx context.Context, username string, limit int, cursor string) (*ConversationsPage, error) {
+	if len(username) < 1 {
+		return nil, errors.New("get user conversations error: username is empty")
+	}
+
+	return em.getConversationsPage(ctx, "get user conversations", path.Join("user", username, "user_channels"), limit, cursor)
+}
+
+// IterateUserConversations 返回用户服务端会话列表的分页迭代器
+// username: 用户 ID, limit: 每页数量
+func (em *Easemob) IterateUserConversations(username string, limit int) *Pager[*Conversation] {
+	return newPager(func(ctx context.Context, cursor string) ([]*Conversation, string, error) {
+		page, e := em.GetUserConversations(ctx, username, limit, cursor)
+		if e != nil {
+			return nil, "", e
+		}
+
+		return page.Conversations, page.Cursor, nil
+	})
+}
+
+func (em *Easemob) getConversationsPage(ctx context.Context, op, subPath string, limit int, cursor string) (*ConversationsPage, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL(subPath)
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if len(cursor) > 0 {
+		q.Set("cursor", cursor)
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError(op, res)
+	}
+
+	resp := &RespCommon[*ConversationsPage]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if resp.Data == nil {
+		return &ConversationsPage{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+// GetGroupConversationList 获取用户在群组上下文中的会话列表
+// groupId: 群组 ID, username: 用户 ID, pageSize: 每页数量, cursor: 分页游标, 首页传空
+func (em *Easemob) GetGroupConversationList(ctx context.Context, groupId, username string, pageSize int, cursor string) (*ConversationPage, error) {
+	if len(groupId) < 1 || len(username) < 1 {
+		return nil, errors.New("get group conversation list error: group id or username is empty")
+	}
+
+	return em.getConversationsPage(ctx, "get group conversation list", path.Join("group", groupId, "user", username, "user_channels"), pageSize, cursor)
+}
+
+type deleteConversationReq struct {
+	Channel    string   `json:"channel"`
+	Type       ChatType `json:"type"`
+	DeleteRoam bool     `json:"delete_roam"`
+}
+
+// DeleteConversation 单向删除用户的服务端会话
+// 单向删除: 只影响 username 自己的会话列表 (以及 deleteRoam 时自己的漫游消息), 对方的会话和消息不受影响
+// username: 用户 ID, channelTo: 会话对方 (用户 ID 或群组 ID), chatType: 会话类型 (chat 或 groupchat), deleteRoam: 是否同时删除漫游消息
+func (em *Easemob) DeleteConversation(ctx context.Context, username, channelTo string, chatType ChatType, deleteRoam bool) error {
+	if len(username) < 1 || len(channelTo) < 1 {
+		return errors.New("delete conversation error: username or channel is empty")
+	}
+
+	if chatType != ChatTypeChat && chatType != ChatTypeGroupChat {
+		return fmt.Errorf("delete conversation error: invalid chat type %q", chatType)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("users", username, "user_channel")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&deleteConversationReq{
+			Channel:    channelTo,
+			Type:       chatType,
+			DeleteRoam: deleteRoam,
+		}))
+	if e != nil {
+		return fmt.Errorf("delete conversation error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("delete conversation", res)
+	}
+
+	return nil
+}
+
+type RoamDeleteOptions struct {
+	Before time.Time // 按时间删除: 删除该时间之前的漫游消息
+	Count  int       // 按条数删除: 每个会话删除最早的 Count 条漫游消息
+}
+
+type deleteRoamingMessagesReq struct {
+	Before int64 `json:"before,omitempty"` // Unix 时间戳, 单位为毫秒
+	Count  int   `json:"count,omitempty"`
+}
+
+// DeleteRoamingMessages 单向删除用户的服务端漫游消息, 不影响会话中的其他参与者
+// opts 中的 Before 和 Count 必须且只能设置一个
+// username: 用户 ID, opts: 删除方式
+func (em *Easemob) DeleteRoamingMessages(ctx context.Context, username string, opts RoamDeleteOptions) error {
+	if len(username) < 1 {
+		return errors.New("delete roaming messages error: username is empty")
+	}
+
+	byTime, byCount := !opts.Before.IsZero(), opts.Count != 0
+	if byTime == byCount {
+		return errors.New("delete roaming messages error: exactly one of before and count must be set")
+	}
+
+	if opts.Count < 0 {
+		return errors.New("delete roaming messages error: count must be positive")
+	}
+
+	req := &deleteRoamingMessagesReq{Count: opts.Count}
+	if byTime {
+		req.Before = opts.Before.UnixMilli()
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("users", username, "roaming_messages")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(req))
+	if e != nil {
+		return fmt.Errorf("delete roaming messages error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("delete roaming messages", res)
+	}
+
+	return nil
+}
+
+type pinConversationReq struct {
+	ConversationId string   `json:"conversationId"`
+	Type           ChatType `json:"type"`
+	IsPinned       bool     `json:"isPinned"`
+}
+
+// PinConversation 置顶或取消置顶用户的会话, 置顶状态会同步到用户的所有设备
+// 请求设置的是目标状态, 重复置顶或重复取消置顶不会返回错误
+// username: 用户 ID, convID: 会话对方 (用户 ID 或群组 ID), convType: 会话类型 (chat 或 groupchat), pinned: 是否置顶
+func (em *Easemob) PinConversation(ctx context.Context, username, convID string, convType ChatType, pinned bool) error {
+	if len(username) < 1 || len(convID) < 1 {
+		return errors.New("pin conversation error: username or conversation id is empty")
+	}
+
+	if convType != ChatTypeChat && convType != ChatTypeGroupChat {
+		return fmt.Errorf("pin conversation error: invalid chat type %q", convType)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Put(em.GetURL(path.Join("user", username, "user_channel/pin")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&pinConversationReq{
+			ConversationId: convID,
+			Type:           convType,
+			IsPinned:       pinned,
+		}))
+	if e != nil {
+		return fmt.Errorf("pin conversation error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("pin conversation", res)
+	}
+
+	return nil
+}
+
+// GetPinnedConversations 获取用户置顶的会话列表
+// username: 用户 ID, limit: 每页数量, cursor: 分页游标, 首页传空
+func (em *Easemob) GetPinnedConversations(ctx context.Context, username string, limit int, cursor string) (*ConversationsPage, error) {
+	if len(username) < 1 {
+		return nil, errors.New("get pinned conversations error: username is empty")
+	}
+
+	return em.getConversationsPage(ctx, "get pinned conversations", path.Join("user", username, "user_channels/pin"), limit, cursor)
+}
+
+type ackConversationReq struct {
+	Channel string   `json:"channel"`
+	Type    ChatType `json:"type"`
+}
+
+// AckConversation 代用户将会话标记为已读, 清空服务端的未读数并同步到用户的所有设备
+// 会话没有未读消息时同样返回成功, 可以重复调用
+// username: 用户 ID, peerOrGroupID: 会话对方 (用户 ID 或群组 ID), chatType: 会话类型 (chat 或 groupchat)
+func (em *Easemob) AckConversation(ctx context.Context, username, peerOrGroupID string, chatType ChatType) error {
+	if len(username) < 1 || len(peerOrGroupID) < 1 {
+		return errors.New("ack conversation error: username or channel is empty")
+	}
+
+	if chatType != ChatTypeChat && chatType != ChatTypeGroupChat {
+		return fmt.Errorf("ack conversation error: invalid chat type %q", chatType)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("users", username, "user_channel/read")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&ackConversationReq{
+			Channel: peerOrGroupID,
+			Type:    chatType,
+		}))
+	if e != nil {
+		return fmt.Errorf("ack conversation error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("ack conversation", res)
+	}
+
+	return nil
+}
+
+// ConversationType 漫游消息的会话类型
+type ConversationType string
+
+const (
+	ConversationTypeChat      ConversationType = "chat"      // 单聊
+	ConversationTypeGroupChat ConversationType = "groupchat" // 群聊
+)
+
+// RoamDirection 拉取漫游消息的方向
+type RoamDirection string
+
+const (
+	RoamDirectionAsc  RoamDirection = "asc"  // 从 startMsg 开始向后拉取更新的消息
+	RoamDirectionDesc RoamDirection = "desc" // 从 startMsg 开始向前拉取更早的消息
+)
+
+// RoamingMessagesMaxLimit 单次拉取漫游消息的最大数量
+const RoamingMessagesMaxLimit = 50
+
+type RoamingMessagePage struct {
+	Messages []*ReceivedMessage // 当前页的消息, 按 dir 指定的方向排列
+	Cursor   string             // 下一页的 startMsg
+	IsLast   bool               // 是否已经没有更多消息
+}
+
+type roamingMessagePageResp struct {
+	Msgs   []json.RawMessage `json:"msgs"`
+	Cursor string            `json:"cursor"`
+	IsLast bool              `json:"isLast"`
+}
+
+// GetConversationRoamingMessages 拉取用户单个会话的漫游消息
+// 翻页时将上一页的 Cursor 作为 startMsg 传入, IsLast 为 true 时停止
+// username: 用户 ID, conversationId: 会话对方 (用户 ID 或群组 ID), convType: 会话类型 (chat 或 groupchat),
+// startMsg: 起始消息 ID, 首页传空, limit: 每页数量, 最多 RoamingMessagesMaxLimit 条, 小于 1 时使用服务端默认值,
+// dir: 拉取方向
+func (em *Easemob) GetConversationRoamingMessages(ctx context.Context, username, conversationId string, convType ConversationType, startMsg string, limit int, dir RoamDirection) (*RoamingMessagePage, error) {
+	if len(username) < 1 || len(conversationId) < 1 {
+		return nil, errors.New("get conversation roaming messages error: username or conversation id is empty")
+	}
+
+	if convType != ConversationTypeChat && convType != ConversationTypeGroupChat {
+		return nil, fmt.Errorf("get conversation roaming messages error: invalid conversation type %q", convType)
+	}
+
+	if dir != RoamDirectionAsc && dir != RoamDirectionDesc {
+		return nil, fmt.Errorf("get conversation roaming messages error: invalid direction %q", dir)
+	}
+
+	if limit > RoamingMessagesMaxLimit {
+		return nil, fmt.Errorf("get conversation roaming messages error: limit must be at most %d", RoamingMessagesMaxLimit)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL(path.Join("user", username, "user_channel", "message", "roaming"))
+	q := url.Values{}
+	q.Set("conversationId", conversationId)
+	q.Set("type", string(convType))
+	q.Set("direction", string(dir))
+	if len(startMsg) > 0 {
+		q.Set("startMsgId", startMsg)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get conversation roaming messages error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get conversation roaming messages", res)
+	}
+
+	resp := &RespCommon[*roamingMessagePageResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get conversation roaming messages error: %w", e)
+	}
+
+	page := &RoamingMessagePage{Messages: []*ReceivedMessage{}, IsLast: true}
+	if resp.Data == nil {
+		return page, nil
+	}
+
+	for _, raw := range resp.Data.Msgs {
+		msg, e := ParseMessagePayload(raw)
+		if e != nil {
+			return nil, fmt.Errorf("get conversation roaming messages error: %w", e)
+		}
+
+		page.Messages = append(page.Messages, msg)
+	}
+
+	// 翻页只依赖服务端返回的游标, 没有游标时无法继续翻页
+	page.Cursor = resp.Data.Cursor
+	page.IsLast = resp.Data.IsLast || len(page.Messages) < 1 || len(page.Cursor) < 1
+
+	return page, nil
+}
@@ -0,0 +1,436 @@
+package easemob
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetGroupConversationList(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/group/g1/user/alice/user_channels", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("limit") != "20" || q.Get("cursor") != "c1" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"channel_infos": []map[string]interface{}{
+					{"channel_id": "org#app_bob@easemob.com", "type": "chat", "unread": 2},
+				},
+				"cursor": "c2",
+			},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	page, e := em.GetGroupConversationList(context.Background(), "g1", "alice", 20, "c1")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if page.Cursor != "c2" || len(page.Conversations) != 1 || page.Conversations[0].Unread != 2 {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	if peer, chatType := page.Conversations[0].Peer(); peer != "bob" || chatType != ChatTypeChat {
+		t.Errorf("unexpected peer: %s, %s", peer, chatType)
+	}
+}
+
+func TestDeleteConversation(t *testing.T) {
+	var got []deleteConversationReq
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /org/app/users/alice/user_channel", func(w http.ResponseWriter, r *http.Request) {
+		req := deleteConversationReq{}
+		decodeBody(t, r, &req)
+		got = append(got, req)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"result": "ok"}})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	want := []deleteConversationReq{
+		{Channel: "bob", Type: ChatTypeChat, DeleteRoam: true},
+		{Channel: "bob", Type: ChatTypeChat},
+		{Channel: "g1", Type: ChatTypeGroupChat, DeleteRoam: true},
+		{Channel: "g1", Type: ChatTypeGroupChat},
+	}
+	for _, v := range want {
+		if e := em.DeleteConversation(ctx, "alice", v.Channel, v.Type, v.DeleteRoam); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected requests: %+v", got)
+	}
+}
+
+func TestDeleteConversationValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	ctx := context.Background()
+
+	if e := em.DeleteConversation(ctx, "", "bob", ChatTypeChat, false); e == nil {
+		t.Error("expected error for empty username")
+	}
+
+	if e := em.DeleteConversation(ctx, "alice", "r1", "chatroom", false); e == nil {
+		t.Error("expected error for invalid chat type")
+	}
+}
+
+func TestDeleteRoamingMessages(t *testing.T) {
+	var bodies [][]byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /org/app/users/alice/roaming_messages", func(w http.ResponseWriter, r *http.Request) {
+		body, e := io.ReadAll(r.Body)
+		if e != nil {
+			t.Error(e)
+		}
+		bodies = append(bodies, body)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	if e := em.DeleteRoamingMessages(ctx, "alice", RoamDeleteOptions{Before: time.UnixMilli(1710000000000)}); e != nil {
+		t.Fatal(e)
+	}
+
+	if e := em.DeleteRoamingMessages(ctx, "alice", RoamDeleteOptions{Count: 50}); e != nil {
+		t.Fatal(e)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("unexpected request count: %d", len(bodies))
+	}
+
+	assertJSON(t, bodies[0], `{"before": 1710000000000}`)
+	assertJSON(t, bodies[1], `{"count": 50}`)
+}
+
+func TestDeleteRoamingMessagesValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	ctx := context.Background()
+
+	for name, opts := range map[string]RoamDeleteOptions{
+		"none":     {},
+		"both":     {Before: time.Now(), Count: 10},
+		"negative": {Count: -1},
+	} {
+		if e := em.DeleteRoamingMessages(ctx, "alice", opts); e == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if e := em.DeleteRoamingMessages(ctx, "", RoamDeleteOptions{Count: 1}); e == nil {
+		t.Error("expected error for empty username")
+	}
+}
+
+func TestIterateUserConversations(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/user/alice/user_channels", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "2" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			_, _ = w.Write([]byte(`{"data": {
+				"channel_infos": [
+					{"channel_id": "org#app_bob@easemob.com", "type": "chat", "unread": 3, "timestamp": 1710000000000,
+					 "last_message": {"id": "m1", "from": "bob", "to": "alice", "timestamp": 1710000000000, "payload": {"bodies": [{"type": "txt", "msg": "hi"}]}}},
+					{"channel_id": "org#app_g1@conference.easemob.com", "type": "groupchat", "timestamp": 1709990000000}
+				],
+				"cursor": "page-2"
+			}}`))
+		case "page-2":
+			_, _ = w.Write([]byte(`{"data": {
+				"channel_infos": [
+					{"channel_id": "org#app_g_2@conference.easemob.com", "type": "groupchat", "unread": 1, "last_message": null}
+				]
+			}}`))
+		default:
+			t.Errorf("unexpected cursor: %q", r.URL.Query().Get("cursor"))
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+
+	conversations, e := em.IterateUserConversations("alice", 2).All(context.Background())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if len(conversations) != 3 {
+		t.Fatalf("unexpected conversation count: %d", len(conversations))
+	}
+
+	want := []struct {
+		peer     string
+		chatType ChatType
+		unread   int
+	}{
+		{"bob", ChatTypeChat, 3},
+		{"g1", ChatTypeGroupChat, 0},
+		{"g_2", ChatTypeGroupChat, 1},
+	}
+	for i, v := range want {
+		peer, chatType := conversations[i].Peer()
+		if peer != v.peer || chatType != v.chatType || conversations[i].Unread != v.unread {
+			t.Errorf("conversation %d: unexpected %s, %s, %d", i, peer, chatType, conversations[i].Unread)
+		}
+	}
+
+	meta, e := conversations[0].LastMessageMeta()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if meta == nil || meta.MsgId != "m1" || meta.From != "bob" || meta.Timestamp != 1710000000000 {
+		t.Errorf("unexpected last message: %+v", meta)
+	}
+
+	for _, v := range conversations[1:] {
+		if meta, e := v.LastMessageMeta(); meta != nil || e != nil {
+			t.Errorf("expected no last message, got %+v, %v", meta, e)
+		}
+	}
+}
+
+func TestPinConversation(t *testing.T) {
+	// 服务端按目标状态保存置顶, 重复置顶不会产生重复的会话
+	pinned := map[string]ChatType{}
+	order := []string{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /org/app/user/alice/user_channel/pin", func(w http.ResponseWriter, r *http.Request) {
+		req := &pinConversationReq{}
+		decodeBody(t, r, req)
+
+		if _, ok := pinned[req.ConversationId]; req.IsPinned && !ok {
+			order = append(order, req.ConversationId)
+		}
+
+		if req.IsPinned {
+			pinned[req.ConversationId] = req.Type
+		} else {
+			delete(pinned, req.ConversationId)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"result": "ok"}})
+	})
+	mux.HandleFunc("GET /org/app/user/alice/user_channels/pin", func(w http.ResponseWriter, r *http.Request) {
+		infos := []map[string]interface{}{}
+		for _, id := range order {
+			if chatType, ok := pinned[id]; ok {
+				infos = append(infos, map[string]interface{}{"channel_id": id, "type": chatType})
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"channel_infos": infos}})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	list := func() []string {
+		t.Helper()
+
+		page, e := em.GetPinnedConversations(ctx, "alice", 10, "")
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		ids := []string{}
+		for _, v := range page.Conversations {
+			ids = append(ids, v.ChannelId+"/"+v.Type)
+		}
+		return ids
+	}
+
+	for _, v := range []struct {
+		id       string
+		chatType ChatType
+	}{{"bob", ChatTypeChat}, {"g1", ChatTypeGroupChat}, {"bob", ChatTypeChat}} {
+		if e := em.PinConversation(ctx, "alice", v.id, v.chatType, true); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	if got := list(); !reflect.DeepEqual(got, []string{"bob/chat", "g1/groupchat"}) {
+		t.Errorf("unexpected pinned after re-pin: %v", got)
+	}
+
+	if e := em.PinConversation(ctx, "alice", "bob", ChatTypeChat, false); e != nil {
+		t.Fatal(e)
+	}
+
+	if got := list(); !reflect.DeepEqual(got, []string{"g1/groupchat"}) {
+		t.Errorf("unexpected pinned after unpin: %v", got)
+	}
+
+	if e := em.PinConversation(ctx, "alice", "r1", "chatroom", true); e == nil {
+		t.Error("expected error for invalid chat type")
+	}
+}
+
+func TestAckConversation(t *testing.T) {
+	// 服务端的未读数, 已读后清零, 没有未读消息时同样返回成功
+	unread := map[string]int{"bob/chat": 3, "g1/groupchat": 5}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/users/alice/user_channel/read", func(w http.ResponseWriter, r *http.Request) {
+		req := &ackConversationReq{}
+		decodeBody(t, r, req)
+
+		unread[req.Channel+"/"+string(req.Type)] = 0
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"result": "ok"}})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if e := em.AckConversation(ctx, "alice", "bob", ChatTypeChat); e != nil {
+			t.Fatal(e)
+		}
+
+		if e := em.AckConversation(ctx, "alice", "g1", ChatTypeGroupChat); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	if !reflect.DeepEqual(unread, map[string]int{"bob/chat": 0, "g1/groupchat": 0}) {
+		t.Errorf("unexpected unread: %v", unread)
+	}
+
+	if e := em.AckConversation(ctx, "alice", "r1", ChatTypeChatroom); e == nil {
+		t.Error("expected error for chatroom")
+	}
+}
+
+func TestGetConversationRoamingMessages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/user/alice/user_channel/message/roaming", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("conversationId") != "g1" || q.Get("type") != "groupchat" || q.Get("direction") != "desc" || q.Get("limit") != "2" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch q.Get("startMsgId") {
+		case "":
+			_, _ = w.Write([]byte(`{"data": {
+				"msgs": [
+					{"msg_id": "m4", "from": "bob", "to": "g1", "chat_type": "groupchat", "timestamp": 1710000004000, "payload": {"bodies": [{"type": "txt", "msg": "4"}]}},
+					{"msg_id": "m3", "from": "bob", "to": "g1", "chat_type": "groupchat", "timestamp": 1710000003000, "payload": {"bodies": [{"type": "txt", "msg": "3"}]}}
+				],
+				"cursor": "m2",
+				"isLast": false
+			}}`))
+		case "m2":
+			_, _ = w.Write([]byte(`{"data": {
+				"msgs": [
+					{"msg_id": "m2", "from": "bob", "to": "g1", "chat_type": "groupchat", "timestamp": 1710000002000, "payload": {"bodies": [{"type": "txt", "msg": "2"}]}}
+				],
+				"isLast": true
+			}}`))
+		default:
+			t.Errorf("unexpected startMsgId: %q", q.Get("startMsgId"))
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	ids := []string{}
+	startMsg := ""
+	for i := 0; i < 3; i++ {
+		page, e := em.GetConversationRoamingMessages(ctx, "alice", "g1", ConversationTypeGroupChat, startMsg, 2, RoamDirectionDesc)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		for _, msg := range page.Messages {
+			ids = append(ids, msg.MsgId)
+		}
+
+		if page.IsLast {
+			break
+		}
+
+		startMsg = page.Cursor
+	}
+
+	if !reflect.DeepEqual(ids, []string{"m4", "m3", "m2"}) {
+		t.Errorf("unexpected messages: %v", ids)
+	}
+}
+
+func TestGetConversationRoamingMessagesNoCursor(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/user/alice/user_channel/message/roaming", func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("type") != "chat" || q.Get("direction") != "asc" || q.Has("limit") || q.Has("startMsgId") {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {
+			"msgs": [
+				{"msg_id": "m1", "from": "bob", "to": "alice", "chat_type": "chat", "timestamp": 1710000001000, "payload": {"bodies": [{"type": "txt", "msg": "1"}]}}
+			],
+			"isLast": false
+		}}`))
+	})
+
+	em := newTestEasemob(t, mux)
+
+	page, e := em.GetConversationRoamingMessages(context.Background(), "alice", "bob", ConversationTypeChat, "", 0, RoamDirectionAsc)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if len(page.Messages) != 1 || len(page.Cursor) > 0 || !page.IsLast {
+		t.Errorf("unexpected page: %d messages, cursor %q, last %v", len(page.Messages), page.Cursor, page.IsLast)
+	}
+}
+
+func TestGetConversationRoamingMessagesValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	ctx := context.Background()
+
+	cases := []struct {
+		username, conversationId string
+		convType                 ConversationType
+		limit                    int
+		dir                      RoamDirection
+	}{
+		{"", "bob", ConversationTypeChat, 10, RoamDirectionAsc},
+		{"alice", "", ConversationTypeChat, 10, RoamDirectionAsc},
+		{"alice", "r1", ConversationType("chatroom"), 10, RoamDirectionAsc},
+		{"alice", "bob", ConversationTypeChat, 10, RoamDirection("up")},
+		{"alice", "bob", ConversationTypeChat, RoamingMessagesMaxLimit + 1, RoamDirectionAsc},
+	}
+	for i, v := range cases {
+		if _, e := em.GetConversationRoamingMessages(ctx, v.username, v.conversationId, v.convType, "", v.limit, v.dir); e == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
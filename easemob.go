@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"path"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 	"uw/ureq"
@@ -14,7 +17,6 @@ import (
 type Easemob struct {
 	mu     *sync.RWMutex // 全局锁
 	exitCh chan struct{} // 退出通道
-	client *ureq.Client  // HTTP 客户端
 
 	baseURL *url.URL // 基础 URL
 	orgName string   // 组织名称
@@ -23,11 +25,39 @@ type Easemob struct {
 	clientId     string // App 的 client_id
 	clientSecret string // App 的 client_secret
 
+	application          string    // 当前 App 的 UUID
 	accessToken          string    // Token 字符串
 	accessTokenExpiresAt time.Time // Token 有效时间
 
-	limiterResetTicker *time.Ticker // 限流重置定时器
-	limiterChan        chan bool    // 限流通道
+	limiterResetTicker *time.Ticker  // 限流重置定时器
+	limiterChan        chan bool     // 限流通道
+	limiterInterval    time.Duration // 限流间隔
+
+	batchConcurrency int // 批量接口的并发数
+
+	historyLocation *time.Location // 历史消息文件按小时划分所使用的时区
+
+	endpointLimiters map[string]*endpointLimiter // 单独限流的接口, 在全局限流之外生效
+
+	chatFileMaxSize int64 // 上传文件的大小上限, 单位为字节
+
+	defaultTimeout time.Duration // 调用方的 context 没有设置截止时间时使用的默认超时时间
+	clientTimeout  time.Duration // SetClientTimeout 设置的 HTTP 客户端超时时间, 每次请求新建客户端时使用
+
+	requestHooks  []func(*http.Request) error         // 请求钩子, 按添加顺序执行
+	responseHooks []func(*http.Response, error) error // 响应钩子, 按添加顺序执行
+}
+
+// Option NewEasemob 的可选项
+type Option func(eb *Easemob)
+
+// WithDefaultTimeout 设置默认超时时间
+// 调用方传入的 context 没有设置截止时间时, 等待限流和每次请求都使用该超时时间,
+// 避免调用方忘记设置截止时间导致调用一直挂起; 不会修改 SetClientTimeout 设置的客户端超时时间
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(eb *Easemob) {
+		eb.defaultTimeout = d
+	}
 }
 
 // NewEasemob 创建 Easemob 实例
@@ -36,7 +66,8 @@ type Easemob struct {
 // appName: 应用名称
 // clientId: App 的 client_id
 // clientSecret: App 的 client_secret
-func NewEasemob(host, orgName, appName, clientId, clientSecret string) (*Easemob, error) {
+// opts: 可选项, 例如 WithDefaultTimeout
+func NewEasemob(host, orgName, appName, clientId, clientSecret string, opts ...Option) (*Easemob, error) {
 	if len(host) < 1 || len(orgName) < 1 || len(appName) < 1 ||
 		len(clientId) < 1 || len(clientSecret) < 1 {
 		return nil, errors.New("invalid params")
@@ -45,7 +76,6 @@ func NewEasemob(host, orgName, appName, clientId, clientSecret string) (*Easemob
 	eb := &Easemob{
 		mu:     &sync.RWMutex{},
 		exitCh: make(chan struct{}),
-		client: ureq.New(),
 
 		baseURL: &url.URL{
 			Scheme: "https",
@@ -59,6 +89,24 @@ func NewEasemob(host, orgName, appName, clientId, clientSecret string) (*Easemob
 
 		limiterResetTicker: time.NewTicker(time.Second),
 		limiterChan:        make(chan bool, 1),
+		limiterInterval:    time.Second,
+
+		batchConcurrency: 4,
+
+		historyLocation: DefaultHistoryLocation,
+
+		endpointLimiters: map[string]*endpointLimiter{
+			LimiterImportMessages:    newEndpointLimiter(100, time.Second),
+			LimiterBroadcastMessages: newEndpointLimiter(1, time.Minute),
+		},
+
+		chatFileMaxSize: ChatFileDefaultMaxSize,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(eb)
+		}
 	}
 
 	go eb.limiter()
@@ -72,7 +120,55 @@ func (eb *Easemob) Close() {
 
 	eb.limiterResetTicker.Stop()
 	close(eb.limiterChan)
-	eb.exitCh <- struct{}{}
+	close(eb.exitCh)
+}
+
+// Clone 复制配置 (服务器, 组织, 应用, 凭证以及各项设置) 创建新的实例
+// 新实例使用独立的锁, HTTP 客户端和限流器 (限流速率与原实例相同), 不复制 Token, 首次调用时重新获取
+// 不再使用时需要调用 Close
+func (eb *Easemob) Clone() *Easemob {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	baseURL := *eb.baseURL
+	endpointLimiters := make(map[string]*endpointLimiter, len(eb.endpointLimiters))
+	for name, l := range eb.endpointLimiters {
+		endpointLimiters[name] = newEndpointLimiter(l.rate, l.interval)
+	}
+
+	clone := &Easemob{
+		mu:     &sync.RWMutex{},
+		exitCh: make(chan struct{}),
+
+		baseURL: &baseURL,
+		orgName: eb.orgName,
+		appName: eb.appName,
+
+		clientId:     eb.clientId,
+		clientSecret: eb.clientSecret,
+
+		limiterResetTicker: time.NewTicker(eb.limiterInterval),
+		limiterChan:        make(chan bool, cap(eb.limiterChan)),
+		limiterInterval:    eb.limiterInterval,
+
+		batchConcurrency: eb.batchConcurrency,
+
+		historyLocation: eb.historyLocation,
+
+		endpointLimiters: endpointLimiters,
+
+		chatFileMaxSize: eb.chatFileMaxSize,
+
+		defaultTimeout: eb.defaultTimeout,
+		clientTimeout:  eb.clientTimeout,
+
+		requestHooks:  append([]func(*http.Request) error{}, eb.requestHooks...),
+		responseHooks: append([]func(*http.Response, error) error{}, eb.responseHooks...),
+	}
+
+	go clone.limiter()
+
+	return clone
 }
 
 // SetLimiter 设置限流
@@ -82,18 +178,70 @@ func (eb *Easemob) SetLimiter(rate uint32, interval time.Duration) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
-	eb.limiterResetTicker = time.NewTicker(interval)
+	// 限流协程正在使用定时器, 只重置间隔而不替换
+	eb.limiterResetTicker.Reset(interval)
 	eb.limiterChan = make(chan bool, rate)
+	eb.limiterInterval = interval
+}
+
+// LimiterImportMessages 导入消息接口 (ImportChatMessage, ImportGroupMessage) 的限流名称, 默认 100 次/秒
+const LimiterImportMessages = "import_messages"
+
+// LimiterBroadcastMessages 全员广播接口 (BroadcastMessage) 的限流名称, 默认 1 次/分钟
+const LimiterBroadcastMessages = "broadcast_messages"
+
+// SetEndpointLimiter 设置单个接口的限流, 在全局限流之外生效
+// name: 限流名称, 例如 LimiterImportMessages
+// rate: 限流速率, 为 0 时取消该接口的单独限流
+// interval: 限流间隔 (重置时间)
+func (eb *Easemob) SetEndpointLimiter(name string, rate uint32, interval time.Duration) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if rate < 1 {
+		delete(eb.endpointLimiters, name)
+		return
+	}
+
+	eb.endpointLimiters[name] = newEndpointLimiter(int(rate), interval)
+}
+
+type RateLimitStatus struct {
+	Limit     int // 每个间隔内允许的请求数
+	Used      int // 当前间隔内已使用的请求数
+	Remaining int // 当前间隔内剩余的请求数
+}
+
+// GetRateLimitStatus 获取本地限流器的当前状态
+func (eb *Easemob) GetRateLimitStatus() *RateLimitStatus {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	limit, used := cap(eb.limiterChan), len(eb.limiterChan)
+	return &RateLimitStatus{
+		Limit:     limit,
+		Used:      used,
+		Remaining: limit - used,
+	}
 }
 
 func (eb *Easemob) limiter() {
 	defer func() { _ = recover() }()
 
 	for {
+		// SetLimiter 可能替换限流通道, 每轮都在读锁下重新获取
+		eb.mu.RLock()
+		ticker, ch := eb.limiterResetTicker, eb.limiterChan
+		eb.mu.RUnlock()
+
 		select {
-		case <-eb.limiterResetTicker.C:
-			for i := 0; i < cap(eb.limiterChan); i++ {
-				<-eb.limiterChan
+		case <-ticker.C:
+			for i := 0; i < cap(ch); i++ {
+				select {
+				case <-ch:
+				case <-eb.exitCh:
+					return
+				}
 			}
 		case <-eb.exitCh:
 			return
@@ -105,7 +253,178 @@ func (eb *Easemob) limiter() {
 func (eb *Easemob) SetClientTimeout(timeout time.Duration) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
-	eb.client = eb.client.Timeout(timeout)
+	eb.clientTimeout = timeout
+}
+
+// AllowInsecure 使用 HTTP 而不是 HTTPS 访问 Easemob, 仅用于测试 (例如 httptest.NewServer)
+func (eb *Easemob) AllowInsecure() {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.baseURL.Scheme = "http"
+}
+
+// SetBatchConcurrency 设置批量接口 (例如 GetUsersMuteStatus) 的并发数
+func (eb *Easemob) SetBatchConcurrency(concurrency int) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.batchConcurrency = max(concurrency, 1)
+}
+
+// SetChatFileMaxSize 设置上传文件 (UploadChatFile 和头像) 的大小上限, 单位为字节
+// 小于 1 时恢复为 ChatFileDefaultMaxSize
+func (eb *Easemob) SetChatFileMaxSize(size int64) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if size < 1 {
+		size = ChatFileDefaultMaxSize
+	}
+	eb.chatFileMaxSize = size
+}
+
+// SetHistoryLocation 设置历史消息文件按小时划分所使用的时区, 需要与集群所在时区一致
+// 默认为 DefaultHistoryLocation (UTC+8), 海外集群需要设置为对应时区
+func (eb *Easemob) SetHistoryLocation(loc *time.Location) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if loc == nil {
+		loc = DefaultHistoryLocation
+	}
+	eb.historyLocation = loc
+}
+
+// SetAccessToken 注入外部管理的 Token (不会请求 Easemob API)
+// 适用于由中心化 Token 服务统一下发 Token 的场景
+// token: Token 字符串
+// expiresAt: Token 过期时间, 必须晚于当前时间
+func (eb *Easemob) SetAccessToken(token string, expiresAt time.Time) error {
+	if len(strings.TrimSpace(token)) < 1 {
+		return errors.New("set access token error: token is empty")
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return errors.New("set access token error: token is expired")
+	}
+
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.accessToken = token
+	eb.accessTokenExpiresAt = expiresAt
+	return nil
+}
+
+// SetRequestHook 设置请求钩子, 替换已有的所有钩子, fn 为 nil 时清空
+// 钩子在 GetBaseClient 和 GetAccessClient 返回客户端前执行, 用于添加自定义请求头 (例如 X-Request-ID),
+// 每次请求都使用新的客户端并重新执行钩子, 清空或替换钩子后, 之前钩子设置的请求头不会再发送,
+// 钩子收到的 *http.Request 只包含请求头, 修改后的请求头会应用到客户端, 钩子返回错误时 Get*Client 返回该错误,
+// 客户端的每个请求头只能有一个值, 同名请求头的多个值以 ", " 合并 (Cookie 以 "; " 合并)
+// 注意: 钩子执行时持有内部读锁, 钩子中不能调用 Easemob 的方法
+func (eb *Easemob) SetRequestHook(fn func(*http.Request) error) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.requestHooks = nil
+	if fn != nil {
+		eb.requestHooks = append(eb.requestHooks, fn)
+	}
+}
+
+// AddRequestHook 追加请求钩子, 多个钩子按添加顺序执行, 参见 SetRequestHook
+func (eb *Easemob) AddRequestHook(fn func(*http.Request) error) {
+	if fn == nil {
+		return
+	}
+
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.requestHooks = append(eb.requestHooks, fn)
+}
+
+// applyRequestHooks 依次执行请求钩子, 并将钩子设置的请求头应用到客户端, 调用方需要持有读锁
+func (eb *Easemob) applyRequestHooks(c *ureq.Client) (*ureq.Client, error) {
+	if len(eb.requestHooks) < 1 {
+		return c, nil
+	}
+
+	req := &http.Request{Header: http.Header{}}
+	for _, fn := range eb.requestHooks {
+		if e := fn(req); e != nil {
+			return nil, fmt.Errorf("request hook error: %w", e)
+		}
+	}
+
+	for k, v := range req.Header {
+		if len(v) < 1 {
+			continue
+		}
+
+		sep := ", "
+		if k == "Cookie" {
+			sep = "; "
+		}
+
+		c = c.Set(k, strings.Join(v, sep))
+	}
+
+	return c, nil
+}
+
+// SetResponseHook 设置响应钩子, 替换已有的所有钩子, fn 为 nil 时清空
+// 每个请求完成后, 在检查状态码和解析 JSON 之前执行, 参数为原始响应 (请求失败时为 nil) 和请求错误,
+// 钩子返回错误时请求以该错误失败 (例如根据 Retry-After 返回自定义错误); 钩子读取响应体后需要重新设置 Body
+func (eb *Easemob) SetResponseHook(fn func(*http.Response, error) error) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.responseHooks = nil
+	if fn != nil {
+		eb.responseHooks = append(eb.responseHooks, fn)
+	}
+}
+
+// AddResponseHook 追加响应钩子, 多个钩子按添加顺序执行, 参见 SetResponseHook
+func (eb *Easemob) AddResponseHook(fn func(*http.Response, error) error) {
+	if fn == nil {
+		return
+	}
+
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.responseHooks = append(eb.responseHooks, fn)
+}
+
+// end 发送请求并依次执行响应钩子
+func (eb *Easemob) end(c *ureq.Client) (*ureq.Response, error) {
+	res, e := c.End()
+
+	eb.mu.RLock()
+	hooks := eb.responseHooks
+	eb.mu.RUnlock()
+
+	if len(hooks) < 1 {
+		return res, e
+	}
+
+	var raw *http.Response
+	if res != nil {
+		raw = res.Response
+	}
+
+	for _, fn := range hooks {
+		if he := fn(raw, e); he != nil {
+			if raw != nil && raw.Body != nil {
+				_ = raw.Body.Close()
+			}
+
+			return nil, fmt.Errorf("response hook error: %w", he)
+		}
+	}
+
+	return res, e
 }
 
 func (eb *Easemob) GetBaseClient(ctx context.Context) (*ureq.Client, error) {
@@ -116,7 +435,12 @@ func (eb *Easemob) GetBaseClient(ctx context.Context) (*ureq.Client, error) {
 	eb.mu.RLock()
 	defer eb.mu.RUnlock()
 
-	return eb.client.Clone(), nil
+	c, e := eb.applyCallTimeout(ctx, eb.newClient())
+	if e != nil {
+		return nil, e
+	}
+
+	return eb.applyRequestHooks(c)
 }
 
 func (eb *Easemob) GetAccessClient(ctx context.Context) (*ureq.Client, error) {
@@ -137,8 +461,37 @@ func (eb *Easemob) GetAccessClient(ctx context.Context) (*ureq.Client, error) {
 
 	defer eb.mu.RUnlock()
 
-	return eb.client.Clone().
-		Set("Authorization", "Bearer "+eb.accessToken), nil
+	c, e := eb.applyCallTimeout(ctx, eb.newClient())
+	if e != nil {
+		return nil, e
+	}
+
+	return eb.applyRequestHooks(c.Set("Authorization", "Bearer "+eb.accessToken))
+}
+
+// withQuery 设置请求的查询参数
+// ureq 只发送 SetQuerySortSlice 中列出的查询参数, 直接写入 URL 的 RawQuery 会被丢弃
+func withQuery(c *ureq.Client, q url.Values) *ureq.Client {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return c.Query(q).SetQuerySortSlice(keys)
+}
+
+// newClient 为单次请求创建新的 HTTP 客户端, 调用方需要持有读锁
+// ureq 的 Clone 与原客户端共用请求头和 *http.Client, 请求头 (例如幂等键) 和超时时间会影响之后的所有请求,
+// 因此每次请求都使用 ureq.New 创建, 连接池由 http.DefaultTransport 复用;
+// 客户端发送后会保存响应和错误, 再次 End 直接返回保存的结果, ureq 也没有重置方法, 所以不能用 sync.Pool 复用客户端
+func (eb *Easemob) newClient() *ureq.Client {
+	c := ureq.New()
+	if eb.clientTimeout > 0 {
+		c = c.Timeout(eb.clientTimeout)
+	}
+
+	return c
 }
 
 func (eb *Easemob) GetURL(subPath string) *url.URL {
@@ -149,11 +502,122 @@ func (eb *Easemob) GetURL(subPath string) *url.URL {
 	})
 }
 
+// GetTokenURL 获取 Token 接口的 URL
+func (eb *Easemob) GetTokenURL() string {
+	return eb.GetURL("token").String()
+}
+
+// GetMessageURL 获取发送消息接口的 URL
+// target: 接收方类型, 例如 users, chatgroups, chatrooms
+func (eb *Easemob) GetMessageURL(target string) string {
+	return eb.GetURL(path.Join("messages", target)).String()
+}
+
+// GetPushSyncURL 获取同步推送接口的 URL
+// target: 推送目标用户 ID, 为空时目标通过请求体传递 (PushSyncMulti)
+func (eb *Easemob) GetPushSyncURL(target string) string {
+	return eb.GetURL(path.Join("push/sync", target)).String()
+}
+
+// GetPushSingleURL 获取异步推送接口的 URL
+func (eb *Easemob) GetPushSingleURL() string {
+	return eb.GetURL("push/single").String()
+}
+
+// getEndpointLimiter 等待单个接口的限流, 未设置时直接返回
+func (eb *Easemob) getEndpointLimiter(ctx context.Context, name string) error {
+	eb.mu.RLock()
+	l, ok := eb.endpointLimiters[name]
+	eb.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := eb.withDefaultTimeout(ctx)
+	defer cancel()
+
+	return l.wait(ctx)
+}
+
+// withDefaultTimeout ctx 没有设置截止时间时使用默认超时时间
+func (eb *Easemob) withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || eb.defaultTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, eb.defaultTimeout)
+}
+
+// applyCallTimeout 设置单次请求的超时时间, 调用方需要持有读锁
+// ctx 设置了截止时间时使用剩余时间, 否则使用默认超时时间, 都不会超过 SetClientTimeout 设置的客户端超时时间
+func (eb *Easemob) applyCallTimeout(ctx context.Context, c *ureq.Client) (*ureq.Client, error) {
+	d := eb.defaultTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if d = time.Until(deadline); d <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+	}
+
+	if d <= 0 || (eb.clientTimeout > 0 && eb.clientTimeout <= d) {
+		return c, nil
+	}
+
+	return c.Timeout(d), nil
+}
+
 func (eb *Easemob) getLimiter(ctx context.Context) error {
+	ctx, cancel := eb.withDefaultTimeout(ctx)
+	defer cancel()
+
+	eb.mu.RLock()
+	ch := eb.limiterChan
+	eb.mu.RUnlock()
+
 	select {
-	case eb.limiterChan <- true:
+	case ch <- true:
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 }
+
+// endpointLimiter 固定窗口限流, 每个 interval 内最多允许 rate 次请求
+type endpointLimiter struct {
+	mu       sync.Mutex
+	rate     int
+	interval time.Duration
+	start    time.Time // 当前窗口的开始时间
+	used     int       // 当前窗口已使用的请求数
+}
+
+func newEndpointLimiter(rate int, interval time.Duration) *endpointLimiter {
+	return &endpointLimiter{rate: rate, interval: interval}
+}
+
+func (l *endpointLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		if now.Sub(l.start) >= l.interval {
+			l.start, l.used = now, 0
+		}
+
+		if l.used < l.rate {
+			l.used++
+			l.mu.Unlock()
+			return nil
+		}
+
+		d := l.start.Add(l.interval).Sub(now)
+		l.mu.Unlock()
+
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
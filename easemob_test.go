@@ -0,0 +1,328 @@
+package easemob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestEasemob 创建请求发往 h 的 Easemob 实例, 已注入 Token 并放宽全局限流
+// 接口路径的前缀为 /org/app/
+func newTestEasemob(t testing.TB, h http.Handler, opts ...Option) *Easemob {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	u, e := url.Parse(srv.URL)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	em, e := NewEasemob(u.Host, "org", "app", "client-id", "client-secret", opts...)
+	if e != nil {
+		t.Fatal(e)
+	}
+	t.Cleanup(em.Close)
+
+	em.AllowInsecure()
+	em.SetLimiter(1000, time.Second)
+	if e := em.SetAccessToken("test-token", time.Now().Add(time.Hour)); e != nil {
+		t.Fatal(e)
+	}
+
+	return em
+}
+
+// writeJSON 以 JSON 写入响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError 写入 Easemob 格式的错误响应
+func writeError(w http.ResponseWriter, status int, errType, description string) {
+	writeJSON(w, status, map[string]string{
+		"error":             errType,
+		"error_description": description,
+	})
+}
+
+// decodeBody 解析请求体, 失败时终止测试
+func decodeBody(t *testing.T, r *http.Request, v interface{}) {
+	t.Helper()
+
+	if e := json.NewDecoder(r.Body).Decode(v); e != nil {
+		t.Errorf("decode request body error: %s", e)
+	}
+}
+
+// failHandler 任何请求都会使测试失败, 用于断言参数校验在发出请求前完成
+func failHandler(t testing.TB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+}
+
+// assertJSON 比较两段 JSON 是否等价 (忽略键的顺序和空白)
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var g, w interface{}
+	if e := json.Unmarshal(got, &g); e != nil {
+		t.Fatalf("invalid json %s: %s", got, e)
+	}
+
+	if e := json.Unmarshal([]byte(want), &w); e != nil {
+		t.Fatalf("invalid expected json %s: %s", want, e)
+	}
+
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("unexpected json:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+// slowHandler 等待 d 后返回用户 alice, 客户端断开时提前返回
+func slowHandler(d time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(d):
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"entities": []map[string]interface{}{{"type": "user", "username": "alice"}},
+			})
+		case <-r.Context().Done():
+		}
+	}
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice", slowHandler(200*time.Millisecond))
+
+	em := newTestEasemob(t, mux, WithDefaultTimeout(50*time.Millisecond))
+
+	// 没有截止时间时使用默认超时时间
+	start := time.Now()
+	if _, e := em.GetUser(context.Background(), "alice"); e == nil {
+		t.Error("expected timeout without deadline")
+	}
+
+	if d := time.Since(start); d >= 200*time.Millisecond {
+		t.Errorf("request was not bounded by the default timeout: %s", d)
+	}
+
+	// 调用方的截止时间优先于默认超时时间
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, e := em.GetUser(ctx, "alice"); e != nil {
+		t.Errorf("request should use the caller deadline: %v", e)
+	}
+}
+
+func TestCallerDeadline(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice", slowHandler(time.Second))
+
+	em := newTestEasemob(t, mux)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, e := em.GetUser(ctx, "alice"); e == nil {
+		t.Error("expected timeout")
+	}
+
+	if d := time.Since(start); d >= time.Second {
+		t.Errorf("request was not bounded by the caller deadline: %s", d)
+	}
+}
+
+func TestCallerDeadlineNotShared(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice", slowHandler(200*time.Millisecond))
+
+	em := newTestEasemob(t, mux)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, e := em.GetUser(ctx, "alice"); e == nil {
+		t.Error("expected timeout")
+	}
+
+	// 截止时间只作用于当前请求, 之后没有截止时间的请求不受影响
+	if _, e := em.GetUser(context.Background(), "alice"); e != nil {
+		t.Errorf("request without deadline should not time out: %v", e)
+	}
+}
+
+func BenchmarkGetAccessClient(b *testing.B) {
+	em := newTestEasemob(b, failHandler(b))
+	em.SetLimiter(1<<16, 10*time.Millisecond)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, e := em.GetAccessClient(ctx); e != nil {
+			b.Fatal(e)
+		}
+	}
+}
+
+func TestRequestHooksReset(t *testing.T) {
+	var got []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-Request-ID"))
+		slowHandler(0)(w, r)
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	em.SetRequestHook(func(r *http.Request) error {
+		r.Header.Set("X-Request-ID", "req-1")
+		return nil
+	})
+
+	if _, e := em.GetUser(ctx, "alice"); e != nil {
+		t.Fatal(e)
+	}
+
+	// 清空钩子后之前设置的请求头不再发送
+	em.SetRequestHook(nil)
+
+	if _, e := em.GetUser(ctx, "alice"); e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(got, []string{"req-1", ""}) {
+		t.Errorf("unexpected request ids: %q", got)
+	}
+}
+
+func TestCloneIsolation(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		headers []http.Header
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Clone())
+		mu.Unlock()
+
+		slowHandler(200*time.Millisecond)(w, r)
+	})
+
+	em := newTestEasemob(t, mux)
+	em.SetRequestHook(func(r *http.Request) error {
+		r.Header.Set("X-Origin", "em")
+		return nil
+	})
+
+	clone := em.Clone()
+	t.Cleanup(clone.Close)
+
+	if e := clone.SetAccessToken("clone-token", time.Now().Add(time.Hour)); e != nil {
+		t.Fatal(e)
+	}
+
+	clone.SetClientTimeout(50 * time.Millisecond)
+	clone.AddRequestHook(func(r *http.Request) error {
+		r.Header.Set("X-Clone", "1")
+		return nil
+	})
+	em.SetRequestHook(nil)
+
+	ctx := context.Background()
+	if _, e := clone.GetUser(ctx, "alice"); e == nil {
+		t.Error("expected clone request to time out")
+	}
+
+	// 副本的超时时间, 钩子和 Token 不影响原实例
+	if _, e := em.GetUser(ctx, "alice"); e != nil {
+		t.Fatalf("original request should not use the clone timeout: %v", e)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(headers) != 2 {
+		t.Fatalf("unexpected request count: %d", len(headers))
+	}
+
+	if h := headers[0]; h.Get("X-Origin") != "em" || h.Get("X-Clone") != "1" || h.Get("Authorization") != "Bearer clone-token" {
+		t.Errorf("unexpected clone headers: %v", h)
+	}
+
+	if h := headers[1]; h.Get("X-Origin") != "" || h.Get("X-Clone") != "" || h.Get("Authorization") != "Bearer test-token" {
+		t.Errorf("unexpected original headers: %v", h)
+	}
+}
+
+func TestRequestHooks(t *testing.T) {
+	var got http.Header
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		slowHandler(0)(w, r)
+	})
+
+	em := newTestEasemob(t, mux)
+	em.SetRequestHook(func(r *http.Request) error {
+		r.Header.Set("X-Request-ID", "req-1")
+		r.Header.Add("Cookie", "a=1")
+		return nil
+	})
+	em.AddRequestHook(func(r *http.Request) error {
+		r.Header.Add("X-Tenant-ID", "t1")
+		r.Header.Add("X-Tenant-ID", "t2")
+		r.Header.Add("Cookie", "b=2")
+		return nil
+	})
+
+	if _, e := em.GetUser(context.Background(), "alice"); e != nil {
+		t.Fatal(e)
+	}
+
+	// 同名请求头的多个值全部保留
+	for k, want := range map[string]string{
+		"X-Request-Id":  "req-1",
+		"X-Tenant-Id":   "t1, t2",
+		"Cookie":        "a=1; b=2",
+		"Authorization": "Bearer test-token",
+	} {
+		if v := strings.Join(got.Values(k), ", "); v != want {
+			t.Errorf("unexpected %s: %q", k, v)
+		}
+	}
+
+	hookErr := errors.New("no tenant")
+	em.AddRequestHook(func(r *http.Request) error { return hookErr })
+
+	if _, e := em.GetAccessClient(context.Background()); !errors.Is(e, hookErr) {
+		t.Errorf("expected hook error from access client, got %v", e)
+	}
+
+	if _, e := em.GetBaseClient(context.Background()); !errors.Is(e, hookErr) {
+		t.Errorf("expected hook error from base client, got %v", e)
+	}
+}
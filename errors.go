@@ -0,0 +1,67 @@
+package easemob
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"uw/ureq"
+)
+
+var (
+	ErrBadRequest      = errors.New("bad request")       // 请求参数错误 (400)
+	ErrUnauthorized    = errors.New("unauthorized")      // 未授权, Token 或凭证无效 (401)
+	ErrForbidden       = errors.New("forbidden")         // 无权限 (403)
+	ErrNotFound        = errors.New("not found")         // 资源不存在 (404)
+	ErrTooManyRequests = errors.New("too many requests") // 超过服务端限流 (429)
+	ErrNotSupported    = errors.New("not supported")     // 当前集群或套餐未开通该功能
+)
+
+// ResponseError Easemob 返回的非 2xx 响应
+// 可以使用 errors.Is 判断 ErrNotFound 等错误类型
+type ResponseError struct {
+	Op          string `json:"-"`                 // 出错的操作
+	StatusCode  int    `json:"-"`                 // HTTP 状态码
+	Status      string `json:"-"`                 // HTTP 状态
+	Body        string `json:"-"`                 // 原始响应内容
+	Type        string `json:"error"`             // 错误类型, 例如 duplicate_unique_property_exists
+	Exception   string `json:"exception"`         // 异常类名
+	Description string `json:"error_description"` // 错误描述
+}
+
+func newResponseError(op string, res *ureq.Response) error {
+	text, _ := res.Text()
+
+	e := &ResponseError{}
+	_ = json.Unmarshal([]byte(text), e)
+
+	e.Op = op
+	e.StatusCode = res.StatusCode
+	e.Status = res.Status
+	e.Body = text
+	return e
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s error: %s, %s", e.Op, e.Status, e.Body)
+}
+
+func (e *ResponseError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		// 凭证错误时 Easemob 可能返回 400 invalid_grant
+		return e.StatusCode == http.StatusUnauthorized || e.Type == "invalid_grant"
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrTooManyRequests:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrNotSupported:
+		return e.Type == "service_not_open"
+	}
+
+	return false
+}
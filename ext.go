@@ -0,0 +1,127 @@
+package easemob
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ExtKeyMaxLen 扩展字段 key 的最大长度
+const ExtKeyMaxLen = 64
+
+// Ext 消息和推送的扩展字段, 序列化为 JSON 对象
+// 可以直接用于 WithExt, PushMessage.Ext, 以及读取 ReceivedMessage.Ext
+type Ext map[string]interface{}
+
+// Set 设置扩展字段, key 不能为空且不能超过 ExtKeyMaxLen,
+// value 只能是字符串, 布尔值, 数字, 或由这些类型组成的 []interface{} 和 map[string]interface{}
+// 零值的 Ext (nil) 会在第一次设置时分配
+func (x *Ext) Set(key string, value interface{}) error {
+	if len(key) < 1 || len(key) > ExtKeyMaxLen {
+		return fmt.Errorf("ext key length must be in [1, %d]", ExtKeyMaxLen)
+	}
+
+	if e := validateExtValue(value); e != nil {
+		return fmt.Errorf("ext %q: %w", key, e)
+	}
+
+	if *x == nil {
+		*x = Ext{}
+	}
+
+	(*x)[key] = value
+	return nil
+}
+
+func validateExtValue(value interface{}) error {
+	switch v := value.(type) {
+	case string, bool,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return errors.New("number must be finite")
+		}
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return errors.New("number must be finite")
+		}
+	case []interface{}:
+		for _, item := range v {
+			if e := validateExtValue(item); e != nil {
+				return e
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if e := validateExtValue(item); e != nil {
+				return e
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value type %T", value)
+	}
+
+	return nil
+}
+
+// GetString 获取字符串类型的扩展字段
+func (x Ext) GetString(key string) (string, bool) {
+	v, ok := x[key].(string)
+	return v, ok
+}
+
+// GetInt 获取整数类型的扩展字段, 支持 Set 接受的所有整数类型 (超出 int64 范围的 uint64 除外),
+// 浮点数 (例如 JSON 解析得到的数字) 只接受没有小数部分的值
+func (x Ext) GetInt(key string) (int64, bool) {
+	switch v := x[key].(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return uintToInt64(uint64(v))
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return uintToInt64(v)
+	case float32:
+		return floatToInt64(float64(v))
+	case float64:
+		return floatToInt64(v)
+	}
+
+	return 0, false
+}
+
+func uintToInt64(v uint64) (int64, bool) {
+	if v > math.MaxInt64 {
+		return 0, false
+	}
+
+	return int64(v), true
+}
+
+func floatToInt64(v float64) (int64, bool) {
+	if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+		return 0, false
+	}
+
+	return int64(v), true
+}
+
+// GetBool 获取布尔类型的扩展字段
+func (x Ext) GetBool(key string) (bool, bool) {
+	v, ok := x[key].(bool)
+	return v, ok
+}
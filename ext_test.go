@@ -0,0 +1,146 @@
+package easemob
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestExtSetAndGet(t *testing.T) {
+	// 零值的 Ext 可以直接使用
+	var x Ext
+
+	for key, value := range map[string]interface{}{
+		"string": "值",
+		"bool":   true,
+		"int":    -1,
+		"int8":   int8(-8),
+		"int16":  int16(16),
+		"int32":  int32(-32),
+		"int64":  int64(1 << 40),
+		"uint":   uint(1),
+		"uint8":  uint8(8),
+		"uint16": uint16(16),
+		"uint32": uint32(32),
+		"uint64": uint64(64),
+		"float":  2.0,
+		"ratio":  0.5,
+		"list":   []interface{}{"a", 1, false},
+		"object": map[string]interface{}{"k": []interface{}{1.5}},
+	} {
+		if e := x.Set(key, value); e != nil {
+			t.Errorf("set %s: %v", key, e)
+		}
+	}
+
+	for key, want := range map[string]int64{
+		"int": -1, "int8": -8, "int16": 16, "int32": -32, "int64": 1 << 40,
+		"uint": 1, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64, "float": 2,
+	} {
+		if v, ok := x.GetInt(key); !ok || v != want {
+			t.Errorf("get int %s: %d, %v", key, v, ok)
+		}
+	}
+
+	for _, key := range []string{"ratio", "string", "bool", "missing"} {
+		if _, ok := x.GetInt(key); ok {
+			t.Errorf("%s should not be read as int", key)
+		}
+	}
+
+	if v, ok := x.GetString("string"); !ok || v != "值" {
+		t.Errorf("unexpected string: %q, %v", v, ok)
+	}
+
+	if v, ok := x.GetBool("bool"); !ok || !v {
+		t.Errorf("unexpected bool: %v, %v", v, ok)
+	}
+
+	if _, ok := x.GetString("int"); ok {
+		t.Error("int should not be read as string")
+	}
+}
+
+func TestExtConstraints(t *testing.T) {
+	x := Ext{}
+
+	for name, v := range map[string]struct {
+		key   string
+		value interface{}
+	}{
+		"empty key":    {"", "v"},
+		"long key":     {strings.Repeat("k", ExtKeyMaxLen+1), "v"},
+		"nan":          {"k", math.NaN()},
+		"inf":          {"k", float32(math.Inf(1))},
+		"struct":       {"k", struct{}{}},
+		"nested":       {"k", []interface{}{map[string]interface{}{"c": make(chan int)}}},
+		"string slice": {"k", []string{"a"}},
+	} {
+		if e := x.Set(v.key, v.value); e == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if len(x) != 0 {
+		t.Errorf("invalid values should not be set: %v", x)
+	}
+
+	if e := x.Set(strings.Repeat("k", ExtKeyMaxLen), "v"); e != nil {
+		t.Error(e)
+	}
+
+	if v, ok := x.GetInt("missing"); ok || v != 0 {
+		t.Errorf("unexpected missing value: %d", v)
+	}
+
+	if v, ok := (Ext{"big": uint64(math.MaxUint64)}).GetInt("big"); ok {
+		t.Errorf("uint64 overflow should not be read as int: %d", v)
+	}
+}
+
+func TestExtRoundTrip(t *testing.T) {
+	var x Ext
+	for key, value := range map[string]interface{}{"orderId": "o-1", "count": uint16(3), "urgent": true} {
+		if e := x.Set(key, value); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	ext, e := json.Marshal(x)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// 序列化为扁平的 JSON 对象
+	assertJSON(t, ext, `{"orderId": "o-1", "count": 3, "urgent": true}`)
+
+	msg, e := ParseMessagePayload([]byte(`{
+		"msg_id": "m1",
+		"payload": {"bodies": [{"type": "txt", "msg": "hi"}], "ext": ` + string(ext) + `}
+	}`))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if v, ok := msg.Ext.GetString("orderId"); !ok || v != "o-1" {
+		t.Errorf("unexpected orderId: %q", v)
+	}
+
+	if v, ok := msg.Ext.GetInt("count"); !ok || v != 3 {
+		t.Errorf("unexpected count: %d", v)
+	}
+
+	if v, ok := msg.Ext.GetBool("urgent"); !ok || !v {
+		t.Errorf("unexpected urgent: %v", v)
+	}
+
+	// 解析得到的 Ext 可以继续修改
+	if e := msg.Ext.Set("handled", true); e != nil {
+		t.Fatal(e)
+	}
+
+	if _, ok := msg.Ext.GetBool("handled"); !ok {
+		t.Error("set on parsed ext was lost")
+	}
+}
@@ -0,0 +1,1354 @@
+package easemob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"uw/ureq"
+)
+
+type ReadEntry struct {
+	UserId string    // 已读用户 ID
+	ReadAt time.Time // 已读时间
+}
+
+type GroupReadReceipt struct {
+	Entries    []*ReadEntry // 已读用户列表
+	TotalCount int          // 已读用户总数
+}
+
+type groupReadReceiptResp struct {
+	TotalCount int `json:"totalCount"`
+	List       []*struct {
+		UserId string `json:"userId"`
+		ReadAt int64  `json:"readAt"` // Unix 时间戳, 单位为毫秒
+	} `json:"list"`
+}
+
+type groupReadReceiptReq struct {
+	UserId string `json:"userId"`
+}
+
+// GetGroupReadReceipts 获取群组消息的已读回执
+// groupId: 群组 ID, msgId: 消息 ID
+func (em *Easemob) GetGroupReadReceipts(ctx context.Context, groupId, msgId string) (*GroupReadReceipt, error) {
+	if len(groupId) < 1 || len(msgId) < 1 {
+		return nil, errors.New("get group read receipts error: group id or msg id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("chatgroups", groupId, "messages", msgId, "read_receipts")).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get group read receipts error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get group read receipts", res)
+	}
+
+	resp := &RespCommon[*groupReadReceiptResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get group read receipts error: %w", e)
+	}
+
+	receipt := &GroupReadReceipt{Entries: []*ReadEntry{}}
+	if resp.Data == nil {
+		return receipt, nil
+	}
+
+	receipt.TotalCount = resp.Data.TotalCount
+	for _, v := range resp.Data.List {
+		receipt.Entries = append(receipt.Entries, &ReadEntry{
+			UserId: v.UserId,
+			ReadAt: time.UnixMilli(v.ReadAt),
+		})
+	}
+
+	return receipt, nil
+}
+
+type ReadReceiptPage struct {
+	Entries []*ReadEntry // 已读用户列表
+	Cursor  string       // 下一页游标, 为空表示没有更多数据
+}
+
+type readReceiptPageResp struct {
+	List []*struct {
+		UserId string `json:"userId"`
+		ReadAt int64  `json:"readAt"` // Unix 时间戳, 单位为毫秒
+	} `json:"list"`
+	Cursor string `json:"cursor"`
+}
+
+// GetGroupMessageReadUsers 分页获取已读群组消息的用户, 消息发送时需要设置 WithGroupAck
+// groupID: 群组 ID, msgID: 消息 ID, limit: 每页数量, cursor: 分页游标, 首页传空
+func (em *Easemob) GetGroupMessageReadUsers(ctx context.Context, groupID, msgID string, limit int, cursor string) (*ReadReceiptPage, error) {
+	if len(groupID) < 1 || len(msgID) < 1 {
+		return nil, errors.New("get group message read users error: group id or msg id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL(path.Join("chatgroups", groupID, "messages", msgID, "ack"))
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if len(cursor) > 0 {
+		q.Set("cursor", cursor)
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get group message read users error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get group message read users", res)
+	}
+
+	resp := &RespCommon[*readReceiptPageResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get group message read users error: %w", e)
+	}
+
+	page := &ReadReceiptPage{Entries: []*ReadEntry{}}
+	if resp.Data == nil {
+		return page, nil
+	}
+
+	page.Cursor = resp.Data.Cursor
+	for _, v := range resp.Data.List {
+		page.Entries = append(page.Entries, &ReadEntry{
+			UserId: v.UserId,
+			ReadAt: time.UnixMilli(v.ReadAt),
+		})
+	}
+
+	return page, nil
+}
+
+// IterateGroupMessageReadUsers 返回已读群组消息用户的分页迭代器
+// groupID: 群组 ID, msgID: 消息 ID, limit: 每页数量
+func (em *Easemob) IterateGroupMessageReadUsers(groupID, msgID string, limit int) *Pager[*ReadEntry] {
+	return newPager(func(ctx context.Context, cursor string) ([]*ReadEntry, string, error) {
+		page, e := em.GetGroupMessageReadUsers(ctx, groupID, msgID, limit, cursor)
+		if e != nil {
+			return nil, "", e
+		}
+
+		return page.Entries, page.Cursor, nil
+	})
+}
+
+// SendGroupReadReceipt 代用户发送群组消息已读回执
+// groupId: 群组 ID, msgId: 消息 ID, userId: 已读用户 ID
+func (em *Easemob) SendGroupReadReceipt(ctx context.Context, groupId, msgId, userId string) error {
+	if len(groupId) < 1 || len(msgId) < 1 || len(userId) < 1 {
+		return errors.New("send group read receipt error: invalid params")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("chatgroups", groupId, "messages", msgId, "read_receipts")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&groupReadReceiptReq{
+			UserId: userId,
+		}))
+	if e != nil {
+		return fmt.Errorf("send group read receipt error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("send group read receipt", res)
+	}
+
+	return nil
+}
+
+type groupUserMetadataReq struct {
+	MetaData map[string]string `json:"metaData"`
+}
+
+type groupUserMetadataGetReq struct {
+	Targets    []string `json:"targets"`
+	Properties []string `json:"properties,omitempty"`
+}
+
+// SetGroupUserAttributes 设置群成员的自定义属性 (例如群昵称)
+// groupId: 群组 ID, username: 群成员 ID, attrs: 属性键值对
+func (em *Easemob) SetGroupUserAttributes(ctx context.Context, groupId, username string, attrs map[string]string) error {
+	if len(groupId) < 1 || len(username) < 1 {
+		return errors.New("set group user attributes error: group id or username is empty")
+	}
+
+	if len(attrs) < 1 {
+		return errors.New("set group user attributes error: attrs is empty")
+	}
+
+	return em.putGroupUserMetadata(ctx, "set group user attributes", groupId, username, attrs)
+}
+
+// GetGroupUserAttributes 获取群成员的自定义属性
+// groupId: 群组 ID, username: 群成员 ID, keys: 属性名, 为空时返回全部属性
+func (em *Easemob) GetGroupUserAttributes(ctx context.Context, groupId, username string, keys []string) (map[string]string, error) {
+	if len(groupId) < 1 || len(username) < 1 {
+		return nil, errors.New("get group user attributes error: group id or username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("metadata/chatgroup", groupId, "get")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&groupUserMetadataGetReq{
+			Targets:    []string{username},
+			Properties: keys,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("get group user attributes error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get group user attributes", res)
+	}
+
+	resp := &RespCommon[map[string]map[string]string]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get group user attributes error: %w", e)
+	}
+
+	if attrs, ok := resp.Data[username]; ok && attrs != nil {
+		return attrs, nil
+	}
+
+	return map[string]string{}, nil
+}
+
+// DeleteGroupUserAttributes 删除群成员的自定义属性 (将属性值置空)
+// groupId: 群组 ID, username: 群成员 ID, keys: 要删除的属性名
+func (em *Easemob) DeleteGroupUserAttributes(ctx context.Context, groupId, username string, keys []string) error {
+	if len(groupId) < 1 || len(username) < 1 {
+		return errors.New("delete group user attributes error: group id or username is empty")
+	}
+
+	if len(keys) < 1 {
+		return errors.New("delete group user attributes error: keys is empty")
+	}
+
+	attrs := make(map[string]string, len(keys))
+	for _, k := range keys {
+		attrs[k] = ""
+	}
+
+	return em.putGroupUserMetadata(ctx, "delete group user attributes", groupId, username, attrs)
+}
+
+func (em *Easemob) putGroupUserMetadata(ctx context.Context, op, groupId, username string, attrs map[string]string) error {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Put(em.GetURL(path.Join("metadata/chatgroup", groupId, "user", username)).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&groupUserMetadataReq{
+			MetaData: attrs,
+		}))
+	if e != nil {
+		return fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if !res.OK() {
+		return newResponseError(op, res)
+	}
+
+	return nil
+}
+
+// GetAllGroupUserAttributes 一次获取所有群成员的自定义属性, 返回以群成员 ID 为键的属性表
+// groupId: 群组 ID, keys: 属性名, 为空时返回全部属性
+func (em *Easemob) GetAllGroupUserAttributes(ctx context.Context, groupId string, keys []string) (map[string]map[string]string, error) {
+	if len(groupId) < 1 {
+		return nil, errors.New("get all group user attributes error: group id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	q := url.Values{}
+	if len(keys) > 0 {
+		q.Set("properties", strings.Join(keys, ","))
+	}
+
+	res, e := em.end(withQuery(c.Get(em.GetURL(path.Join("metadata/chatgroup", groupId, "user")).String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get all group user attributes error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get all group user attributes", res)
+	}
+
+	resp := &RespCommon[map[string]map[string]string]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get all group user attributes error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return map[string]map[string]string{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+type groupMessageIdsReq struct {
+	MsgIds []string `json:"msg_ids"`
+}
+
+// DeleteGroupMessages 清空用户在群组中的漫游消息
+// 失败时返回 *ResponseError, 包含服务端返回的错误类型和描述
+// groupId: 群组 ID, username: 用户 ID
+func (em *Easemob) DeleteGroupMessages(ctx context.Context, groupId, username string) error {
+	if len(groupId) < 1 || len(username) < 1 {
+		return errors.New("delete group messages error: group id or username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("chatgroups", groupId, "msg/roaming/user", username)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("delete group messages error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("delete group messages", res)
+	}
+
+	return nil
+}
+
+// DeleteGroupMessageByIds 删除群组中的指定消息, 用于内容审核删除违规消息
+// 失败时返回 *ResponseError, 包含服务端返回的错误类型和描述
+// groupId: 群组 ID, msgIds: 消息 ID 列表
+func (em *Easemob) DeleteGroupMessageByIds(ctx context.Context, groupId string, msgIds []string) error {
+	if len(groupId) < 1 || len(msgIds) < 1 {
+		return errors.New("delete group message by ids error: group id or msg ids is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("chatgroups", groupId, "msg/roaming")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&groupMessageIdsReq{
+			MsgIds: msgIds,
+		}))
+	if e != nil {
+		return fmt.Errorf("delete group message by ids error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("delete group message by ids", res)
+	}
+
+	return nil
+}
+
+type GroupInviteMode string
+
+const (
+	GroupInviteAnyMember GroupInviteMode = "any_member" // 所有成员都可以邀请
+	GroupInviteAdminOnly GroupInviteMode = "admin_only" // 只有群主和管理员可以邀请
+)
+
+type GroupJoinMode string
+
+const (
+	GroupJoinAnyMember GroupJoinMode = "any_member" // 任何用户都可以直接加入, 无需审批
+	GroupJoinAdminOnly GroupJoinMode = "admin_only" // 加入申请需要群主或管理员审批
+)
+
+type groupInviteModeReq struct {
+	AllowInvites bool `json:"allowinvites"`
+}
+
+type groupJoinModeReq struct {
+	MembersOnly bool `json:"membersonly"`
+}
+
+// SetGroupInviteMode 设置群组的邀请权限, 对应群组的 allowinvites 设置
+// groupId: 群组 ID, mode: 邀请权限
+func (em *Easemob) SetGroupInviteMode(ctx context.Context, groupId string, mode GroupInviteMode) error {
+	if len(groupId) < 1 {
+		return errors.New("set group invite mode error: group id is empty")
+	}
+
+	switch mode {
+	case GroupInviteAnyMember, GroupInviteAdminOnly:
+	default:
+		return fmt.Errorf("set group invite mode error: invalid mode %q", mode)
+	}
+
+	return em.putGroupSettings(ctx, "set group invite mode", groupId, &groupInviteModeReq{
+		AllowInvites: mode == GroupInviteAnyMember,
+	})
+}
+
+// SetGroupJoinMode 设置群组的加入方式, 对应群组的 membersonly 设置
+// groupId: 群组 ID, mode: 加入方式
+func (em *Easemob) SetGroupJoinMode(ctx context.Context, groupId string, mode GroupJoinMode) error {
+	if len(groupId) < 1 {
+		return errors.New("set group join mode error: group id is empty")
+	}
+
+	switch mode {
+	case GroupJoinAnyMember, GroupJoinAdminOnly:
+	default:
+		return fmt.Errorf("set group join mode error: invalid mode %q", mode)
+	}
+
+	return em.putGroupSettings(ctx, "set group join mode", groupId, &groupJoinModeReq{
+		MembersOnly: mode == GroupJoinAdminOnly,
+	})
+}
+
+func (em *Easemob) putGroupSettings(ctx context.Context, op, groupId string, req interface{}) error {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Put(em.GetURL(path.Join("chatgroups", groupId)).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(req))
+	if e != nil {
+		return fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if !res.OK() {
+		return newResponseError(op, res)
+	}
+
+	return nil
+}
+
+type GroupApplication struct {
+	Applicant string    // 申请人用户 ID
+	Reason    string    // 申请理由
+	AppliedAt time.Time // 申请时间
+	Status    string    // 申请状态, 例如 pending, approved, rejected
+}
+
+type groupApplicationResp struct {
+	Applicant string `json:"applicant"`
+	Reason    string `json:"reason"`
+	AppliedAt int64  `json:"applied_at"` // Unix 时间戳, 单位为毫秒
+	Status    string `json:"status"`
+}
+
+// GetGroupApplications 分页获取群组的入群申请, 用于需要审批 (membersonly) 的群组
+// groupId: 群组 ID, pageNum: 页码 (从 1 开始), pageSize: 每页数量
+func (em *Easemob) GetGroupApplications(ctx context.Context, groupId string, pageNum, pageSize int) ([]*GroupApplication, error) {
+	if len(groupId) < 1 {
+		return nil, errors.New("get group applications error: group id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL(path.Join("chatgroups", groupId, "applications"))
+	q := url.Values{}
+	if pageNum > 0 {
+		q.Set("pagenum", strconv.Itoa(pageNum))
+	}
+	if pageSize > 0 {
+		q.Set("pagesize", strconv.Itoa(pageSize))
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get group applications error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get group applications", res)
+	}
+
+	resp := &RespCommon[[]*groupApplicationResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get group applications error: %w", e)
+	}
+
+	applications := make([]*GroupApplication, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		applications = append(applications, &GroupApplication{
+			Applicant: v.Applicant,
+			Reason:    v.Reason,
+			AppliedAt: time.UnixMilli(v.AppliedAt),
+			Status:    v.Status,
+		})
+	}
+
+	return applications, nil
+}
+
+type groupApplicationReq struct {
+	Applicant string `json:"applicant"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// ApproveGroupApplication 同意入群申请
+// groupId: 群组 ID, applicantUsername: 申请人用户 ID
+func (em *Easemob) ApproveGroupApplication(ctx context.Context, groupId string, applicantUsername string) error {
+	return em.handleGroupApplication(ctx, "approve group application", "approve", groupId, &groupApplicationReq{
+		Applicant: applicantUsername,
+	})
+}
+
+// RejectGroupApplication 拒绝入群申请
+// groupId: 群组 ID, applicantUsername: 申请人用户 ID, reason: 拒绝理由, 可为空
+func (em *Easemob) RejectGroupApplication(ctx context.Context, groupId string, applicantUsername string, reason string) error {
+	return em.handleGroupApplication(ctx, "reject group application", "reject", groupId, &groupApplicationReq{
+		Applicant: applicantUsername,
+		Reason:    reason,
+	})
+}
+
+func (em *Easemob) handleGroupApplication(ctx context.Context, op, action, groupId string, req *groupApplicationReq) error {
+	if len(groupId) < 1 || len(req.Applicant) < 1 {
+		return fmt.Errorf("%s error: group id or applicant is empty", op)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("chatgroups", groupId, "applications", action)).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(req))
+	if e != nil {
+		return fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if !res.OK() {
+		return newResponseError(op, res)
+	}
+
+	return nil
+}
+
+type GroupInvitation struct {
+	Invitee   string    // 被邀请人用户 ID
+	Inviter   string    // 邀请人用户 ID
+	InvitedAt time.Time // 邀请时间
+	ExpireAt  time.Time // 邀请过期时间
+}
+
+type groupInvitationResp struct {
+	Invitee   string `json:"invitee"`
+	Inviter   string `json:"inviter"`
+	InvitedAt int64  `json:"invited_at"` // Unix 时间戳, 单位为毫秒
+	ExpireAt  int64  `json:"expire_at"`  // Unix 时间戳, 单位为毫秒
+}
+
+// GetGroupInvitations 分页获取群组中尚未处理的入群邀请
+// groupId: 群组 ID, pageNum: 页码 (从 1 开始), pageSize: 每页数量
+func (em *Easemob) GetGroupInvitations(ctx context.Context, groupId string, pageNum, pageSize int) ([]*GroupInvitation, error) {
+	if len(groupId) < 1 {
+		return nil, errors.New("get group invitations error: group id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL(path.Join("chatgroups", groupId, "invitations"))
+	q := url.Values{}
+	if pageNum > 0 {
+		q.Set("pagenum", strconv.Itoa(pageNum))
+	}
+	if pageSize > 0 {
+		q.Set("pagesize", strconv.Itoa(pageSize))
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get group invitations error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get group invitations", res)
+	}
+
+	resp := &RespCommon[[]*groupInvitationResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get group invitations error: %w", e)
+	}
+
+	invitations := make([]*GroupInvitation, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		invitations = append(invitations, &GroupInvitation{
+			Invitee:   v.Invitee,
+			Inviter:   v.Inviter,
+			InvitedAt: time.UnixMilli(v.InvitedAt),
+			ExpireAt:  time.UnixMilli(v.ExpireAt),
+		})
+	}
+
+	return invitations, nil
+}
+
+// CancelGroupInvitation 撤销尚未处理的入群邀请
+// groupId: 群组 ID, invitee: 被邀请人用户 ID
+func (em *Easemob) CancelGroupInvitation(ctx context.Context, groupId, invitee string) error {
+	if len(groupId) < 1 || len(invitee) < 1 {
+		return errors.New("cancel group invitation error: group id or invitee is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("chatgroups", groupId, "invitations", invitee)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("cancel group invitation error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("cancel group invitation", res)
+	}
+
+	return nil
+}
+
+type GroupBlacklistEntry struct {
+	Member    string    // 被加入黑名单的用户 ID
+	Reason    string    // 加入黑名单的原因, 可能为空
+	BlockedAt time.Time // 加入黑名单的时间
+}
+
+// GroupBlacklist 群组黑名单
+type GroupBlacklist = []*GroupBlacklistEntry
+
+type groupBlacklistEntryResp struct {
+	Member    string `json:"member"`
+	Reason    string `json:"reason"`
+	BlockedAt int64  `json:"blocked_at"` // Unix 时间戳, 单位为毫秒
+}
+
+// GetGroupBlacklist 分页获取群组黑名单, 黑名单中的用户无法再次加入群组
+// groupId: 群组 ID, pageNum: 页码 (从 1 开始), pageSize: 每页数量
+func (em *Easemob) GetGroupBlacklist(ctx context.Context, groupId string, pageNum, pageSize int) (GroupBlacklist, error) {
+	if len(groupId) < 1 {
+		return nil, errors.New("get group blacklist error: group id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL(path.Join("chatgroups", groupId, "blocks/users"))
+	q := url.Values{}
+	if pageNum > 0 {
+		q.Set("pagenum", strconv.Itoa(pageNum))
+	}
+	if pageSize > 0 {
+		q.Set("pagesize", strconv.Itoa(pageSize))
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get group blacklist error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get group blacklist", res)
+	}
+
+	resp := &RespCommon[[]*groupBlacklistEntryResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get group blacklist error: %w", e)
+	}
+
+	entries := make(GroupBlacklist, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		entries = append(entries, &GroupBlacklistEntry{
+			Member:    v.Member,
+			Reason:    v.Reason,
+			BlockedAt: time.UnixMilli(v.BlockedAt),
+		})
+	}
+
+	return entries, nil
+}
+
+// 创建群组的限制
+const (
+	GroupMaxUsersCeiling   = 3000 // 群组最大成员数的上限 (以套餐为准), 包括群主
+	GroupCreateMaxMembers  = 100  // 创建群组时最多可以添加的成员数, 不包括群主
+	GroupCustomMaxLen      = 1024 // 群组扩展信息的最大长度
+	GroupDefaultMaxUsers   = 200  // 未设置最大成员数时的默认值
+	groupNameMaxLen        = 128
+	groupDescriptionMaxLen = 512
+)
+
+type CreateGroupRequest struct {
+	GroupName         string   `json:"groupname"`           // 群组名称, 不能超过 128 个字符
+	Description       string   `json:"desc,omitempty"`      // 群组描述, 不能超过 512 个字符
+	Public            bool     `json:"public"`              // 是否为公开群
+	MaxUsers          int      `json:"maxusers,omitempty"`  // 最大成员数 (包括群主), 为 0 时为 GroupDefaultMaxUsers
+	AllowInvites      bool     `json:"allowinvites"`        // 是否允许普通成员邀请用户入群
+	MembersOnly       bool     `json:"membersonly"`         // 用户申请入群是否需要审批
+	InviteNeedConfirm bool     `json:"invite_need_confirm"` // 受邀用户是否需要确认后才能入群
+	Owner             string   `json:"owner"`               // 群主
+	Members           []string `json:"members,omitempty"`   // 群成员, 不包括群主
+	Custom            string   `json:"custom,omitempty"`    // 群组扩展信息
+}
+
+type createGroupResp struct {
+	GroupId string `json:"groupid"`
+}
+
+// CreateGroup 创建群组, 返回群组 ID
+// req: 群组信息
+func (em *Easemob) CreateGroup(ctx context.Context, req CreateGroupRequest) (string, error) {
+	if len(req.GroupName) < 1 || len(req.Owner) < 1 {
+		return "", errors.New("create group error: group name or owner is empty")
+	}
+
+	if utf8.RuneCountInString(req.GroupName) > groupNameMaxLen {
+		return "", fmt.Errorf("create group error: group name length > %d", groupNameMaxLen)
+	}
+
+	if utf8.RuneCountInString(req.Description) > groupDescriptionMaxLen {
+		return "", fmt.Errorf("create group error: description length > %d", groupDescriptionMaxLen)
+	}
+
+	if req.MaxUsers < 0 || req.MaxUsers > GroupMaxUsersCeiling {
+		return "", fmt.Errorf("create group error: max users must be 0 (default) or in [1, %d]", GroupMaxUsersCeiling)
+	}
+
+	if len(req.Members) > GroupCreateMaxMembers {
+		return "", fmt.Errorf("create group error: members length > %d", GroupCreateMaxMembers)
+	}
+
+	maxUsers := req.MaxUsers
+	if maxUsers == 0 {
+		maxUsers = GroupDefaultMaxUsers
+	}
+	if len(req.Members)+1 > maxUsers {
+		return "", fmt.Errorf("create group error: members exceed max users %d", maxUsers)
+	}
+
+	if len(req.Custom) > GroupCustomMaxLen {
+		return "", fmt.Errorf("create group error: custom length > %d", GroupCustomMaxLen)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return "", fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("chatgroups").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&req))
+	if e != nil {
+		return "", fmt.Errorf("create group error: %w", e)
+	}
+
+	if !res.OK() {
+		return "", newResponseError("create group", res)
+	}
+
+	resp := &RespCommon[*createGroupResp]{}
+	if e = res.JSON(resp); e != nil {
+		return "", fmt.Errorf("create group error: %w", e)
+	}
+
+	if resp.Data == nil || len(resp.Data.GroupId) < 1 {
+		return "", errors.New("create group error: group id is empty")
+	}
+
+	return resp.Data.GroupId, nil
+}
+
+// GroupsGetMaxBatch GetGroups 单次请求的最大群组数
+const GroupsGetMaxBatch = 100
+
+// ErrGroupNotFound 群组不存在或已解散
+var ErrGroupNotFound = errors.New("group not found")
+
+type GroupInfo struct {
+	Id                string    // 群组 ID
+	Name              string    // 群组名称
+	Description       string    // 群组描述
+	Owner             string    // 群主
+	MemberCount       int       // 当前成员数 (包括群主)
+	MaxUsers          int       // 最大成员数
+	MuteAll           bool      // 是否全员禁言
+	Public            bool      // 是否为公开群
+	MembersOnly       bool      // 用户申请入群是否需要审批
+	AllowInvites      bool      // 是否允许普通成员邀请用户入群
+	InviteNeedConfirm bool      // 受邀用户是否需要确认后才能入群
+	Custom            string    // 群组扩展信息
+	CreatedAt         time.Time // 创建时间
+}
+
+type groupInfoResp struct {
+	Id                string `json:"id"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	Owner             string `json:"owner"`
+	AffiliationsCount int    `json:"affiliations_count"`
+	MaxUsers          int    `json:"maxusers"`
+	Mute              bool   `json:"mute"`
+	Public            bool   `json:"public"`
+	MembersOnly       bool   `json:"membersonly"`
+	AllowInvites      bool   `json:"allowinvites"`
+	InviteNeedConfirm bool   `json:"invite_need_confirm"`
+	Custom            string `json:"custom"`
+	Created           int64  `json:"created"` // Unix 时间戳, 单位为毫秒
+}
+
+func (r *groupInfoResp) info() GroupInfo {
+	return GroupInfo{
+		Id:                r.Id,
+		Name:              r.Name,
+		Description:       r.Description,
+		Owner:             r.Owner,
+		MemberCount:       r.AffiliationsCount,
+		MaxUsers:          r.MaxUsers,
+		MuteAll:           r.Mute,
+		Public:            r.Public,
+		MembersOnly:       r.MembersOnly,
+		AllowInvites:      r.AllowInvites,
+		InviteNeedConfirm: r.InviteNeedConfirm,
+		Custom:            r.Custom,
+		CreatedAt:         time.UnixMilli(r.Created),
+	}
+}
+
+// GetGroups 批量获取群组详情, 不存在的群组不会出现在结果中
+// groupIDs: 群组 ID, 最多 GroupsGetMaxBatch 个
+func (em *Easemob) GetGroups(ctx context.Context, groupIDs ...string) ([]GroupInfo, error) {
+	if len(groupIDs) < 1 || len(groupIDs) > GroupsGetMaxBatch {
+		return nil, fmt.Errorf("get groups error: group ids length must be in [1, %d]", GroupsGetMaxBatch)
+	}
+
+	for _, v := range groupIDs {
+		if len(v) < 1 || strings.Contains(v, ",") {
+			return nil, fmt.Errorf("get groups error: invalid group id %q", v)
+		}
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("chatgroups", strings.Join(groupIDs, ","))).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get groups error: %w", e)
+	}
+
+	if !res.OK() {
+		e := newResponseError("get groups", res)
+		if errors.Is(e, ErrNotFound) {
+			return nil, fmt.Errorf("%w: %w", ErrGroupNotFound, e)
+		}
+
+		return nil, e
+	}
+
+	resp := &RespCommon[[]*groupInfoResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get groups error: %w", e)
+	}
+
+	groups := make([]GroupInfo, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		if v != nil && len(v.Id) > 0 {
+			groups = append(groups, v.info())
+		}
+	}
+
+	return groups, nil
+}
+
+// GetGroup 获取单个群组详情, 群组不存在时返回的错误满足 errors.Is(e, ErrGroupNotFound)
+// groupID: 群组 ID
+func (em *Easemob) GetGroup(ctx context.Context, groupID string) (*GroupInfo, error) {
+	groups, e := em.GetGroups(ctx, groupID)
+	if e != nil {
+		return nil, e
+	}
+
+	for i := range groups {
+		if groups[i].Id == groupID {
+			return &groups[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("get group error: %w", ErrGroupNotFound)
+}
+
+// GroupUpdate 修改群组信息, 只发送非 nil 的字段
+type GroupUpdate struct {
+	GroupName         *string `json:"groupname,omitempty"`           // 群组名称
+	Description       *string `json:"description,omitempty"`         // 群组描述
+	MaxUsers          *int    `json:"maxusers,omitempty"`            // 最大成员数
+	MembersOnly       *bool   `json:"membersonly,omitempty"`         // 用户申请入群是否需要审批
+	AllowInvites      *bool   `json:"allowinvites,omitempty"`        // 是否允许普通成员邀请用户入群
+	InviteNeedConfirm *bool   `json:"invite_need_confirm,omitempty"` // 受邀用户是否需要确认后才能入群
+	Custom            *string `json:"custom,omitempty"`              // 群组扩展信息
+}
+
+// GroupUpdateError 服务端拒绝修改部分字段
+type GroupUpdateError struct {
+	GroupId  string   // 群组 ID
+	Rejected []string // 修改失败的字段, 例如 maxusers
+}
+
+func (e *GroupUpdateError) Error() string {
+	return fmt.Sprintf("update group error: %s rejected fields: %s", e.GroupId, strings.Join(e.Rejected, ", "))
+}
+
+// UpdateGroup 修改群组信息
+// 服务端拒绝修改部分字段时返回 *GroupUpdateError, 其余字段已经修改成功
+// groupID: 群组 ID, update: 需要修改的字段
+func (em *Easemob) UpdateGroup(ctx context.Context, groupID string, update GroupUpdate) error {
+	if len(groupID) < 1 {
+		return errors.New("update group error: group id is empty")
+	}
+
+	if update == (GroupUpdate{}) {
+		return errors.New("update group error: nothing to update")
+	}
+
+	if update.MaxUsers != nil && (*update.MaxUsers < 1 || *update.MaxUsers > GroupMaxUsersCeiling) {
+		return fmt.Errorf("update group error: max users must be in [1, %d]", GroupMaxUsersCeiling)
+	}
+
+	if update.Custom != nil && len(*update.Custom) > GroupCustomMaxLen {
+		return fmt.Errorf("update group error: custom length > %d", GroupCustomMaxLen)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Put(em.GetURL(path.Join("chatgroups", groupID)).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&update))
+	if e != nil {
+		return fmt.Errorf("update group error: %w", e)
+	}
+
+	if !res.OK() {
+		e := newResponseError("update group", res)
+		if errors.Is(e, ErrNotFound) {
+			return fmt.Errorf("%w: %w", ErrGroupNotFound, e)
+		}
+
+		return e
+	}
+
+	resp := &RespCommon[map[string]interface{}]{}
+	if e = res.JSON(resp); e != nil {
+		return fmt.Errorf("update group error: %w", e)
+	}
+
+	rejected := []string{}
+	for field, v := range resp.Data {
+		if ok, isBool := v.(bool); isBool && !ok {
+			rejected = append(rejected, field)
+		}
+	}
+
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+		return &GroupUpdateError{GroupId: groupID, Rejected: rejected}
+	}
+
+	return nil
+}
+
+type deleteGroupResp struct {
+	Success bool   `json:"success"`
+	GroupId string `json:"groupid"`
+}
+
+// DeleteGroup 解散群组
+// 群组不存在或已解散时返回的错误满足 errors.Is(e, ErrGroupNotFound), 清理任务可以视为已删除;
+// 没有权限时满足 errors.Is(e, ErrForbidden)
+// groupID: 群组 ID
+func (em *Easemob) DeleteGroup(ctx context.Context, groupID string) error {
+	if len(groupID) < 1 {
+		return errors.New("delete group error: group id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("chatgroups", groupID)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("delete group error: %w", e)
+	}
+
+	if !res.OK() {
+		e := newResponseError("delete group", res)
+		if errors.Is(e, ErrNotFound) {
+			return fmt.Errorf("%w: %w", ErrGroupNotFound, e)
+		}
+
+		return e
+	}
+
+	resp := &RespCommon[*deleteGroupResp]{}
+	if e = res.JSON(resp); e != nil {
+		return fmt.Errorf("delete group error: %w", e)
+	}
+
+	if resp.Data != nil && len(resp.Data.GroupId) > 0 && resp.Data.GroupId != groupID {
+		return fmt.Errorf("delete group error: deleted group id %q does not match %q", resp.Data.GroupId, groupID)
+	}
+
+	return nil
+}
+
+// 获取 App 下所有群组时每页数量的取值范围, 超出范围的 limit 会被调整到边界值
+const (
+	GroupsListDefaultLimit = 10  // limit 小于 1 时使用的默认值
+	GroupsListMaxLimit     = 100 // 每页最大数量
+)
+
+type GroupSummary struct {
+	Id           string    // 群组 ID
+	Name         string    // 群组名称
+	Owner        string    // 群主的用户 ID
+	MemberCount  int       // 当前成员数 (包括群主)
+	LastModified time.Time // 群组最近一次修改的时间
+}
+
+type GroupsPage struct {
+	Groups []*GroupSummary // 当前页的群组列表
+	Cursor string          // 下一页游标, 为空表示没有更多数据
+}
+
+type groupSummaryResp struct {
+	GroupId      string      `json:"groupid"`
+	GroupName    string      `json:"groupname"`
+	Owner        string      `json:"owner"`        // 格式为 {org}#{app}_{username}
+	Affiliations int         `json:"affiliations"` // 成员数
+	LastModified json.Number `json:"lastModified"` // Unix 时间戳, 单位为毫秒, 服务端以字符串返回
+}
+
+// ListGroups 分页获取 App 下的所有群组
+// limit: 每页数量, 会被调整到 [1, GroupsListMaxLimit], 小于 1 时使用 GroupsListDefaultLimit
+// cursor: 分页游标, 首页传空
+func (em *Easemob) ListGroups(ctx context.Context, limit int, cursor string) (*GroupsPage, error) {
+	if limit < 1 {
+		limit = GroupsListDefaultLimit
+	} else if limit > GroupsListMaxLimit {
+		limit = GroupsListMaxLimit
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL("chatgroups")
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	if len(cursor) > 0 {
+		q.Set("cursor", cursor)
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("list groups error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("list groups", res)
+	}
+
+	resp := &RespCommon[[]*groupSummaryResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("list groups error: %w", e)
+	}
+
+	ownerPrefix := em.orgName + "#" + em.appName + "_"
+	page := &GroupsPage{Groups: []*GroupSummary{}, Cursor: resp.Cursor}
+	for _, v := range resp.Data {
+		summary := &GroupSummary{
+			Id:          v.GroupId,
+			Name:        v.GroupName,
+			Owner:       strings.TrimPrefix(v.Owner, ownerPrefix),
+			MemberCount: v.Affiliations,
+		}
+
+		if len(v.LastModified) > 0 {
+			ms, e := v.LastModified.Int64()
+			if e != nil {
+				return nil, fmt.Errorf("list groups error: invalid last modified %q of group %s", v.LastModified, v.GroupId)
+			}
+			summary.LastModified = time.UnixMilli(ms)
+		}
+
+		page.Groups = append(page.Groups, summary)
+	}
+
+	return page, nil
+}
+
+// IterateGroups 返回 App 下所有群组的分页迭代器
+// limit: 每页数量, 取值规则同 ListGroups
+func (em *Easemob) IterateGroups(limit int) *Pager[*GroupSummary] {
+	return newPager(func(ctx context.Context, cursor string) ([]*GroupSummary, string, error) {
+		page, e := em.ListGroups(ctx, limit, cursor)
+		if e != nil {
+			return nil, "", e
+		}
+
+		return page.Groups, page.Cursor, nil
+	})
+}
+
+// GroupAddMembersMaxBatch AddGroupMembers 单次请求的最大用户数
+const GroupAddMembersMaxBatch = 60
+
+type GroupMemberChangeResult struct {
+	Added          []string         // 本次新加入群组的用户
+	AlreadyMembers []string         // 请求前已经在群组中的用户
+	Failed         map[string]error // 请求失败的用户及对应的错误, 仅 AddGroupMembersAll 会填充
+}
+
+type addGroupMemberResp struct {
+	Result  bool   `json:"result"`
+	GroupId string `json:"groupid"`
+	User    string `json:"user"`
+}
+
+// AddGroupMember 添加单个群组成员, 不需要用户确认
+// groupID: 群组 ID, username: 用户 ID
+func (em *Easemob) AddGroupMember(ctx context.Context, groupID, username string) error {
+	if len(groupID) < 1 || len(username) < 1 {
+		return errors.New("add group member error: group id or username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("chatgroups", groupID, "users", username)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("add group member error: %w", e)
+	}
+
+	if !res.OK() {
+		e := newResponseError("add group member", res)
+		if errors.Is(e, ErrNotFound) {
+			return fmt.Errorf("%w: %w", ErrGroupNotFound, e)
+		}
+
+		return e
+	}
+
+	resp := &RespCommon[*addGroupMemberResp]{}
+	if e = res.JSON(resp); e != nil {
+		return fmt.Errorf("add group member error: %w", e)
+	}
+
+	if resp.Data == nil || !resp.Data.Result {
+		return fmt.Errorf("add group member error: add %s to group %s failed", username, groupID)
+	}
+
+	return nil
+}
+
+type addGroupMembersReq struct {
+	Usernames []string `json:"usernames"`
+}
+
+type addGroupMembersResp struct {
+	NewMembers []string `json:"newmembers"`
+	GroupId    string   `json:"groupid"`
+}
+
+// AddGroupMembers 批量添加群组成员, 不需要用户确认
+// 服务端只返回新加入的用户, 请求中其余的用户视为已经在群组中
+// groupID: 群组 ID, usernames: 用户 ID, 最多 GroupAddMembersMaxBatch 个, 超出时使用 AddGroupMembersAll
+func (em *Easemob) AddGroupMembers(ctx context.Context, groupID string, usernames []string) (*GroupMemberChangeResult, error) {
+	if len(groupID) < 1 {
+		return nil, errors.New("add group members error: group id is empty")
+	}
+
+	if len(usernames) < 1 || len(usernames) > GroupAddMembersMaxBatch {
+		return nil, fmt.Errorf("add group members error: usernames length must be in [1, %d]", GroupAddMembersMaxBatch)
+	}
+
+	for _, username := range usernames {
+		if len(username) < 1 {
+			return nil, errors.New("add group members error: username is empty")
+		}
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("chatgroups", groupID, "users")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&addGroupMembersReq{Usernames: usernames}))
+	if e != nil {
+		return nil, fmt.Errorf("add group members error: %w", e)
+	}
+
+	if !res.OK() {
+		e := newResponseError("add group members", res)
+		if errors.Is(e, ErrNotFound) {
+			return nil, fmt.Errorf("%w: %w", ErrGroupNotFound, e)
+		}
+
+		return nil, e
+	}
+
+	resp := &RespCommon[*addGroupMembersResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("add group members error: %w", e)
+	}
+
+	added := map[string]bool{}
+	if resp.Data != nil {
+		for _, username := range resp.Data.NewMembers {
+			added[username] = true
+		}
+	}
+
+	result := &GroupMemberChangeResult{
+		Added:          []string{},
+		AlreadyMembers: []string{},
+		Failed:         map[string]error{},
+	}
+	for _, username := range usernames {
+		if added[username] {
+			result.Added = append(result.Added, username)
+		} else {
+			result.AlreadyMembers = append(result.AlreadyMembers, username)
+		}
+	}
+
+	return result, nil
+}
+
+// AddGroupMembersAll 不限数量的 AddGroupMembers, 每 GroupAddMembersMaxBatch 个用户为一批依次请求
+// 同一群组的批次不并发, 避免服务端群组人数上限的校验相互竞争;
+// 失败批次中的用户记录在返回结果的 Failed 中, 不影响后续批次, 所有失败会合并到返回的错误中
+// groupID: 群组 ID, usernames: 用户 ID
+func (em *Easemob) AddGroupMembersAll(ctx context.Context, groupID string, usernames []string) (*GroupMemberChangeResult, error) {
+	if len(groupID) < 1 {
+		return nil, errors.New("add group members all error: group id is empty")
+	}
+
+	if len(usernames) < 1 {
+		return nil, errors.New("add group members all error: usernames is empty")
+	}
+
+	result := &GroupMemberChangeResult{
+		Added:          []string{},
+		AlreadyMembers: []string{},
+		Failed:         map[string]error{},
+	}
+	errs := []error{}
+
+	for start := 0; start < len(usernames); start += GroupAddMembersMaxBatch {
+		batch := usernames[start:min(start+GroupAddMembersMaxBatch, len(usernames))]
+
+		r, e := em.AddGroupMembers(ctx, groupID, batch)
+		if e != nil {
+			errs = append(errs, fmt.Errorf("batch %d: %w", start/GroupAddMembersMaxBatch, e))
+
+			// 群组不存在或 ctx 已取消时后续批次也不会成功, 剩余用户全部记为失败
+			fatal := errors.Is(e, ErrGroupNotFound) || ctx.Err() != nil
+			if fatal {
+				batch = usernames[start:]
+			}
+
+			for _, username := range batch {
+				result.Failed[username] = e
+			}
+
+			if fatal {
+				break
+			}
+			continue
+		}
+
+		result.Added = append(result.Added, r.Added...)
+		result.AlreadyMembers = append(result.AlreadyMembers, r.AlreadyMembers...)
+	}
+
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,896 @@
+package easemob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetGroupInviteAndJoinMode(t *testing.T) {
+	var bodies []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /org/app/chatgroups/g1", func(w http.ResponseWriter, r *http.Request) {
+		body, e := io.ReadAll(r.Body)
+		if e != nil {
+			t.Error(e)
+		}
+		bodies = append(bodies, string(body))
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]bool{}})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	for _, mode := range []GroupInviteMode{GroupInviteAnyMember, GroupInviteAdminOnly} {
+		if e := em.SetGroupInviteMode(ctx, "g1", mode); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	for _, mode := range []GroupJoinMode{GroupJoinAnyMember, GroupJoinAdminOnly} {
+		if e := em.SetGroupJoinMode(ctx, "g1", mode); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	want := []string{
+		`{"allowinvites": true}`,
+		`{"allowinvites": false}`,
+		`{"membersonly": false}`,
+		`{"membersonly": true}`,
+	}
+	if len(bodies) != len(want) {
+		t.Fatalf("unexpected request count: %d", len(bodies))
+	}
+
+	for i, v := range want {
+		assertJSON(t, []byte(bodies[i]), v)
+	}
+
+	// 无效的模式不发出请求
+	if e := em.SetGroupInviteMode(ctx, "g1", "owner_only"); e == nil {
+		t.Error("expected error for invalid invite mode")
+	}
+
+	if e := em.SetGroupJoinMode(ctx, "g1", "owner_only"); e == nil {
+		t.Error("expected error for invalid join mode")
+	}
+
+	if len(bodies) != len(want) {
+		t.Errorf("invalid modes should not be sent: %v", bodies[len(want):])
+	}
+}
+
+func TestSendGroupMessageGroupAck(t *testing.T) {
+	rec := &messageRecorder{}
+	em := newTestEasemob(t, rec.handler(t))
+	ctx := context.Background()
+
+	if _, e := em.SendGroupMessage(ctx, "alice", []string{"g1"}, &TextBody{Msg: "hi"}, WithGroupAck()); e != nil {
+		t.Fatal(e)
+	}
+
+	req := map[string]interface{}{}
+	if e := json.Unmarshal(rec.last(t), &req); e != nil {
+		t.Fatal(e)
+	}
+
+	if req["need_group_ack"] != true {
+		t.Errorf("unexpected need_group_ack: %v", req["need_group_ack"])
+	}
+
+	if _, e := em.SendGroupMessage(ctx, "alice", []string{"g1"}, &TextBody{Msg: "hi"}); e != nil {
+		t.Fatal(e)
+	}
+
+	req = map[string]interface{}{}
+	if e := json.Unmarshal(rec.last(t), &req); e != nil {
+		t.Fatal(e)
+	}
+
+	if _, ok := req["need_group_ack"]; ok {
+		t.Error("need_group_ack should be omitted by default")
+	}
+
+	// 已读回执只对群聊消息有效
+	if _, e := em.SendMessage(ctx, TargetUsers, "alice", []string{"bob"}, &TextBody{Msg: "hi"}, WithGroupAck()); e == nil {
+		t.Error("expected error for group ack on single chat")
+	}
+}
+
+func TestIterateGroupMessageReadUsers(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatgroups/g1/messages/m1/ack", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("limit") != "2" {
+			t.Errorf("unexpected limit: %q", q.Get("limit"))
+		}
+
+		switch q.Get("cursor") {
+		case "":
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"data": map[string]interface{}{
+					"list": []map[string]interface{}{
+						{"userId": "bob", "readAt": 1710000000000},
+						{"userId": "carol", "readAt": 1710000001000},
+					},
+					"cursor": "page-2",
+				},
+			})
+		case "page-2":
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"data": map[string]interface{}{
+					"list": []map[string]interface{}{{"userId": "dave", "readAt": 1710000002000}},
+				},
+			})
+		default:
+			t.Errorf("unexpected cursor: %q", q.Get("cursor"))
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+
+	entries, e := em.IterateGroupMessageReadUsers("g1", "m1", 2).All(context.Background())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := []*ReadEntry{
+		{UserId: "bob", ReadAt: time.UnixMilli(1710000000000)},
+		{UserId: "carol", ReadAt: time.UnixMilli(1710000001000)},
+		{UserId: "dave", ReadAt: time.UnixMilli(1710000002000)},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
+	var bodies [][]byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/chatgroups", func(w http.ResponseWriter, r *http.Request) {
+		body, e := io.ReadAll(r.Body)
+		if e != nil {
+			t.Error(e)
+		}
+		bodies = append(bodies, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"action": "post", "data": {"groupid": "0066119845199873"}}`))
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	id, e := em.CreateGroup(ctx, CreateGroupRequest{
+		GroupName:    "公开群",
+		Description:  "描述",
+		Public:       true,
+		MaxUsers:     500,
+		AllowInvites: true,
+		Owner:        "alice",
+		Members:      []string{"bob", "carol"},
+		Custom:       "ext",
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// 群组 ID 保持字符串, 前导零不会丢失
+	if id != "0066119845199873" {
+		t.Errorf("unexpected group id: %q", id)
+	}
+
+	if _, e := em.CreateGroup(ctx, CreateGroupRequest{
+		GroupName:         "私有群",
+		MembersOnly:       true,
+		InviteNeedConfirm: true,
+		Owner:             "alice",
+	}); e != nil {
+		t.Fatal(e)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("unexpected request count: %d", len(bodies))
+	}
+
+	assertJSON(t, bodies[0], `{
+		"groupname": "公开群", "desc": "描述", "public": true, "maxusers": 500,
+		"allowinvites": true, "membersonly": false, "invite_need_confirm": false,
+		"owner": "alice", "members": ["bob", "carol"], "custom": "ext"
+	}`)
+	assertJSON(t, bodies[1], `{
+		"groupname": "私有群", "public": false, "allowinvites": false,
+		"membersonly": true, "invite_need_confirm": true, "owner": "alice"
+	}`)
+}
+
+func TestCreateGroupValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	members := make([]string, GroupCreateMaxMembers)
+	for i := range members {
+		members[i] = "u" + strconv.Itoa(i)
+	}
+
+	for name, req := range map[string]CreateGroupRequest{
+		"empty owner":         {GroupName: "g"},
+		"negative max users":  {GroupName: "g", Owner: "alice", MaxUsers: -1},
+		"max users ceiling":   {GroupName: "g", Owner: "alice", MaxUsers: GroupMaxUsersCeiling + 1},
+		"too many members":    {GroupName: "g", Owner: "alice", Members: append(members, "extra")},
+		"members > max users": {GroupName: "g", Owner: "alice", MaxUsers: 2, Members: []string{"bob", "carol"}},
+	} {
+		if _, e := em.CreateGroup(context.Background(), req); e == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// groupsFixture 群组详情接口返回的群组, 不存在的群组不会出现在 data 中
+var groupsFixture = map[string]string{
+	"g1": `{
+		"id": "g1", "name": "一组", "description": "描述", "owner": "alice",
+		"affiliations_count": 3, "maxusers": 200, "mute": true, "public": true,
+		"membersonly": false, "allowinvites": true, "invite_need_confirm": false,
+		"custom": "ext", "created": 1710000000000
+	}`,
+	"g3": `{"id": "g3", "name": "三组", "owner": "bob", "affiliations_count": 1, "membersonly": true, "created": 1710000001000}`,
+}
+
+func TestGetGroups(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatgroups/{ids}", func(w http.ResponseWriter, r *http.Request) {
+		data := []string{}
+		for _, id := range strings.Split(r.PathValue("ids"), ",") {
+			if v, ok := groupsFixture[id]; ok {
+				data = append(data, v)
+			}
+		}
+
+		if len(data) < 1 {
+			writeError(w, http.StatusNotFound, "service_resource_not_found", "group not found")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"action": "get", "data": [` + strings.Join(data, ",") + `]}`))
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	g1 := GroupInfo{
+		Id:           "g1",
+		Name:         "一组",
+		Description:  "描述",
+		Owner:        "alice",
+		MemberCount:  3,
+		MaxUsers:     200,
+		MuteAll:      true,
+		Public:       true,
+		AllowInvites: true,
+		Custom:       "ext",
+		CreatedAt:    time.UnixMilli(1710000000000),
+	}
+
+	group, e := em.GetGroup(ctx, "g1")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(*group, g1) {
+		t.Errorf("unexpected group: %+v", group)
+	}
+
+	// g2 不存在, 只返回存在的群组
+	groups, e := em.GetGroups(ctx, "g1", "g2", "g3")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	g3 := GroupInfo{Id: "g3", Name: "三组", Owner: "bob", MemberCount: 1, MembersOnly: true, CreatedAt: time.UnixMilli(1710000001000)}
+	if !reflect.DeepEqual(groups, []GroupInfo{g1, g3}) {
+		t.Errorf("unexpected groups: %+v", groups)
+	}
+
+	if _, e := em.GetGroup(ctx, "g2"); !errors.Is(e, ErrGroupNotFound) || !errors.Is(e, ErrNotFound) {
+		t.Errorf("expected group not found, got %v", e)
+	}
+
+	if _, e := em.GetGroups(ctx, "g1", "a,b"); e == nil {
+		t.Error("expected error for group id containing comma")
+	}
+}
+
+func TestUpdateGroup(t *testing.T) {
+	var bodies [][]byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /org/app/chatgroups/g1", func(w http.ResponseWriter, r *http.Request) {
+		body, e := io.ReadAll(r.Body)
+		if e != nil {
+			t.Error(e)
+		}
+		bodies = append(bodies, body)
+
+		// 超过套餐上限的最大成员数被拒绝, 其余字段修改成功
+		data := map[string]bool{}
+		fields := map[string]interface{}{}
+		if e := json.Unmarshal(body, &fields); e != nil {
+			t.Error(e)
+		}
+		for k, v := range fields {
+			data[k] = !(k == "maxusers" && v.(float64) > 500)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"action": "put", "data": data})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	name, maxUsers, membersOnly := "新名称", 300, false
+	if e := em.UpdateGroup(ctx, "g1", GroupUpdate{GroupName: &name, MaxUsers: &maxUsers, MembersOnly: &membersOnly}); e != nil {
+		t.Fatal(e)
+	}
+
+	custom, bigger := "", 1000
+	e := em.UpdateGroup(ctx, "g1", GroupUpdate{Custom: &custom, MaxUsers: &bigger})
+
+	ue := &GroupUpdateError{}
+	if !errors.As(e, &ue) {
+		t.Fatalf("expected group update error, got %v", e)
+	}
+
+	if ue.GroupId != "g1" || !reflect.DeepEqual(ue.Rejected, []string{"maxusers"}) {
+		t.Errorf("unexpected update error: %+v", ue)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("unexpected request count: %d", len(bodies))
+	}
+
+	// 只发送设置了的字段, 零值也会发送
+	assertJSON(t, bodies[0], `{"groupname": "新名称", "maxusers": 300, "membersonly": false}`)
+	assertJSON(t, bodies[1], `{"maxusers": 1000, "custom": ""}`)
+}
+
+func TestUpdateGroupValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	ctx := context.Background()
+
+	if e := em.UpdateGroup(ctx, "g1", GroupUpdate{}); e == nil {
+		t.Error("expected error for empty update")
+	}
+
+	for _, v := range []int{0, GroupMaxUsersCeiling + 1} {
+		if e := em.UpdateGroup(ctx, "g1", GroupUpdate{MaxUsers: &v}); e == nil {
+			t.Errorf("expected error for max users %d", v)
+		}
+	}
+}
+
+func TestDeleteGroup(t *testing.T) {
+	deleted := map[string]bool{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /org/app/chatgroups/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch id := r.PathValue("id"); {
+		case id == "locked":
+			writeError(w, http.StatusForbidden, "forbidden_op", "no permission to delete group")
+		case id == "wrong":
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"success": true, "groupid": "g1"}})
+		case deleted[id]:
+			writeError(w, http.StatusNotFound, "resource_not_found", "group "+id+" does not exist")
+		default:
+			deleted[id] = true
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"success": true, "groupid": id}})
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	if e := em.DeleteGroup(ctx, "g1"); e != nil {
+		t.Fatal(e)
+	}
+
+	// 重复删除视为已删除
+	if e := em.DeleteGroup(ctx, "g1"); !errors.Is(e, ErrGroupNotFound) || !errors.Is(e, ErrNotFound) {
+		t.Errorf("expected group not found, got %v", e)
+	}
+
+	e := em.DeleteGroup(ctx, "locked")
+	if !errors.Is(e, ErrForbidden) || errors.Is(e, ErrGroupNotFound) {
+		t.Errorf("expected forbidden error, got %v", e)
+	}
+
+	if e := em.DeleteGroup(ctx, "wrong"); e == nil || errors.Is(e, ErrGroupNotFound) {
+		t.Errorf("expected group id mismatch error, got %v", e)
+	}
+}
+
+func TestIterateGroups(t *testing.T) {
+	pages := map[string]string{
+		"": `{"data": [
+			{"groupid": "g1", "groupname": "一组", "owner": "org#app_alice", "affiliations": 3, "lastModified": "1710000000000"},
+			{"groupid": "g2", "groupname": "二组", "owner": "org#app_bob", "affiliations": 1, "lastModified": "1710000001000"}
+		], "cursor": "page-2"}`,
+		"page-2": `{"data": [
+			{"groupid": "g3", "groupname": "三组", "owner": "org#app_carol", "affiliations": 2, "lastModified": "1710000002000"},
+			{"groupid": "g4", "groupname": "四组", "owner": "org#app_dave", "affiliations": 5}
+		], "cursor": "page-3"}`,
+		// 最后一页没有 cursor 字段
+		"page-3": `{"data": [{"groupid": "g5", "groupname": "五组", "owner": "org#app_erin", "affiliations": 4, "lastModified": "1710000004000"}]}`,
+	}
+
+	var cursors []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatgroups", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("limit") != "2" {
+			t.Errorf("unexpected limit: %q", q.Get("limit"))
+		}
+
+		cursors = append(cursors, q.Get("cursor"))
+		page, ok := pages[q.Get("cursor")]
+		if !ok {
+			t.Errorf("unexpected cursor: %q", q.Get("cursor"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(page))
+	})
+
+	em := newTestEasemob(t, mux)
+
+	groups, e := em.IterateGroups(2).All(context.Background())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(cursors, []string{"", "page-2", "page-3"}) {
+		t.Errorf("unexpected cursors: %q", cursors)
+	}
+
+	if len(groups) != 5 {
+		t.Fatalf("unexpected group count: %d", len(groups))
+	}
+
+	want := &GroupSummary{Id: "g1", Name: "一组", Owner: "alice", MemberCount: 3, LastModified: time.UnixMilli(1710000000000)}
+	if !reflect.DeepEqual(groups[0], want) {
+		t.Errorf("unexpected group: %+v", groups[0])
+	}
+
+	if groups[3].Id != "g4" || !groups[3].LastModified.IsZero() || groups[4].Owner != "erin" {
+		t.Errorf("unexpected groups: %+v, %+v", groups[3], groups[4])
+	}
+}
+
+func TestListGroupsLimit(t *testing.T) {
+	var limits []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatgroups", func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": []interface{}{}})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	for _, v := range []int{0, -1, 50, GroupsListMaxLimit + 1} {
+		page, e := em.ListGroups(context.Background(), v, "")
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		if len(page.Groups) != 0 || page.Cursor != "" {
+			t.Errorf("unexpected page: %+v", page)
+		}
+	}
+
+	if !reflect.DeepEqual(limits, []string{"10", "10", "50", "100"}) {
+		t.Errorf("unexpected limits: %q", limits)
+	}
+}
+
+// groupMemberServer 模拟群组 g1 的成员, 包含 fail 中用户的批量请求返回服务端错误
+type groupMemberServer struct {
+	members map[string]bool
+	fail    map[string]bool
+	batches [][]string
+}
+
+func (s *groupMemberServer) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/chatgroups/{id}/users/{username}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "g1" {
+			writeError(w, http.StatusNotFound, "resource_not_found", "group does not exist")
+			return
+		}
+
+		username := r.PathValue("username")
+		s.members[username] = true
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"result": true, "groupid": "g1", "action": "add_member", "user": username},
+		})
+	})
+	mux.HandleFunc("POST /org/app/chatgroups/{id}/users", func(w http.ResponseWriter, r *http.Request) {
+		req := &addGroupMembersReq{}
+		decodeBody(t, r, req)
+		s.batches = append(s.batches, req.Usernames)
+
+		if r.PathValue("id") != "g1" {
+			writeError(w, http.StatusNotFound, "resource_not_found", "group does not exist")
+			return
+		}
+
+		for _, v := range req.Usernames {
+			if s.fail[v] {
+				writeError(w, http.StatusInternalServerError, "internal_error", "try again later")
+				return
+			}
+		}
+
+		// 服务端只返回新加入的用户
+		added := []string{}
+		for _, v := range req.Usernames {
+			if !s.members[v] {
+				s.members[v] = true
+				added = append(added, v)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"newmembers": added, "groupid": "g1", "action": "add_member"},
+		})
+	})
+
+	return mux
+}
+
+func TestAddGroupMember(t *testing.T) {
+	s := &groupMemberServer{members: map[string]bool{}}
+	em := newTestEasemob(t, s.handler(t))
+	ctx := context.Background()
+
+	if e := em.AddGroupMember(ctx, "g1", "alice"); e != nil {
+		t.Fatal(e)
+	}
+
+	if !s.members["alice"] {
+		t.Error("alice was not added")
+	}
+
+	if e := em.AddGroupMember(ctx, "missing", "alice"); !errors.Is(e, ErrGroupNotFound) {
+		t.Errorf("expected group not found, got %v", e)
+	}
+}
+
+func TestAddGroupMembers(t *testing.T) {
+	s := &groupMemberServer{members: map[string]bool{"bob": true, "dave": true}}
+	em := newTestEasemob(t, s.handler(t))
+
+	result, e := em.AddGroupMembers(context.Background(), "g1", []string{"alice", "bob", "carol", "dave"})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := &GroupMemberChangeResult{
+		Added:          []string{"alice", "carol"},
+		AlreadyMembers: []string{"bob", "dave"},
+		Failed:         map[string]error{},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if _, e := em.AddGroupMembers(context.Background(), "g1", pushTargets(GroupAddMembersMaxBatch+1)); e == nil {
+		t.Error("expected error for too many usernames")
+	}
+
+	if len(s.batches) != 1 {
+		t.Errorf("invalid batch should not be sent: %d", len(s.batches))
+	}
+}
+
+func TestAddGroupMembersAll(t *testing.T) {
+	// 130 个用户分为 60, 60, 10 三批, 第二批失败
+	s := &groupMemberServer{members: map[string]bool{"u0": true, "u125": true}, fail: map[string]bool{"u70": true}}
+	em := newTestEasemob(t, s.handler(t))
+
+	usernames := pushTargets(130)
+	result, e := em.AddGroupMembersAll(context.Background(), "g1", usernames)
+
+	re := &ResponseError{}
+	if !errors.As(e, &re) || re.StatusCode != http.StatusInternalServerError || !strings.Contains(e.Error(), "batch 1:") {
+		t.Errorf("expected batch 1 to fail, got %v", e)
+	}
+
+	if len(s.batches) != 3 || len(s.batches[0]) != 60 || len(s.batches[1]) != 60 || len(s.batches[2]) != 10 {
+		t.Fatalf("unexpected batches: %d", len(s.batches))
+	}
+
+	if len(result.Added) != 68 || !reflect.DeepEqual(result.AlreadyMembers, []string{"u0", "u125"}) {
+		t.Errorf("unexpected result: %d added, already %v", len(result.Added), result.AlreadyMembers)
+	}
+
+	if len(result.Failed) != 60 || result.Failed["u60"] == nil || result.Failed["u119"] == nil {
+		t.Errorf("unexpected failures: %d", len(result.Failed))
+	}
+
+	// 群组不存在时剩余批次不再发出
+	s.batches = nil
+	result, e = em.AddGroupMembersAll(context.Background(), "missing", usernames)
+	if !errors.Is(e, ErrGroupNotFound) {
+		t.Fatalf("expected group not found, got %v", e)
+	}
+
+	if len(s.batches) != 1 || len(result.Failed) != len(usernames) {
+		t.Errorf("unexpected batches %d and failures %d", len(s.batches), len(result.Failed))
+	}
+}
+
+func TestGroupReadReceipts(t *testing.T) {
+	var readers []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatgroups/g1/messages/m1/read_receipts", func(w http.ResponseWriter, r *http.Request) {
+		// 已读回执接口没有查询参数
+		if r.URL.RawQuery != "" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"totalCount": 5,
+				"list": []map[string]interface{}{
+					{"userId": "bob", "readAt": 1710000000000},
+					{"userId": "carol", "readAt": 1710000001000},
+				},
+			},
+		})
+	})
+	mux.HandleFunc("POST /org/app/chatgroups/g1/messages/m1/read_receipts", func(w http.ResponseWriter, r *http.Request) {
+		req := &groupReadReceiptReq{}
+		decodeBody(t, r, req)
+		readers = append(readers, req.UserId)
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	if e := em.SendGroupReadReceipt(ctx, "g1", "m1", "dave"); e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(readers, []string{"dave"}) {
+		t.Errorf("unexpected readers: %v", readers)
+	}
+
+	receipt, e := em.GetGroupReadReceipts(ctx, "g1", "m1")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := &GroupReadReceipt{
+		TotalCount: 5,
+		Entries: []*ReadEntry{
+			{UserId: "bob", ReadAt: time.UnixMilli(1710000000000)},
+			{UserId: "carol", ReadAt: time.UnixMilli(1710000001000)},
+		},
+	}
+	if !reflect.DeepEqual(receipt, want) {
+		t.Errorf("unexpected receipt: %+v", receipt)
+	}
+
+	if e := em.SendGroupReadReceipt(ctx, "g1", "m1", ""); e == nil {
+		t.Error("expected error for empty user id")
+	}
+}
+
+func TestGetAllGroupUserAttributes(t *testing.T) {
+	var queries []url.Values
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/metadata/chatgroup/g1/user", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]map[string]string{
+				"alice": {"nickname": "爱丽丝", "role": "host"},
+				"bob":   {"nickname": "鲍勃"},
+			},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	attrs, e := em.GetAllGroupUserAttributes(ctx, "g1", []string{"nickname", "role"})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if attrs["alice"]["role"] != "host" || attrs["bob"]["nickname"] != "鲍勃" {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+
+	// 不指定属性时不发送 properties
+	if _, e := em.GetAllGroupUserAttributes(ctx, "g1", nil); e != nil {
+		t.Fatal(e)
+	}
+
+	want := []url.Values{{"properties": {"nickname,role"}}, {}}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("unexpected queries: %v", queries)
+	}
+}
+
+func TestGroupApplications(t *testing.T) {
+	var query string
+	var actions, bodies []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatgroups/g1/applications", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [
+			{"applicant": "alice", "reason": "老同学", "applied_at": 1710000000000, "status": "pending"}
+		]}`))
+	})
+	mux.HandleFunc("POST /org/app/chatgroups/g1/applications/{action}", func(w http.ResponseWriter, r *http.Request) {
+		body, e := io.ReadAll(r.Body)
+		if e != nil {
+			t.Error(e)
+		}
+
+		actions = append(actions, r.PathValue("action"))
+		bodies = append(bodies, string(body))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]bool{"result": true}})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	applications, e := em.GetGroupApplications(ctx, "g1", 2, 10)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if query != "pagenum=2&pagesize=10" {
+		t.Errorf("unexpected query: %s", query)
+	}
+
+	want := &GroupApplication{
+		Applicant: "alice",
+		Reason:    "老同学",
+		AppliedAt: time.UnixMilli(1710000000000),
+		Status:    "pending",
+	}
+	if len(applications) != 1 || !reflect.DeepEqual(applications[0], want) {
+		t.Errorf("unexpected applications: %+v", applications)
+	}
+
+	if e := em.ApproveGroupApplication(ctx, "g1", "alice"); e != nil {
+		t.Fatal(e)
+	}
+
+	if e := em.RejectGroupApplication(ctx, "g1", "bob", "群已满"); e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(actions, []string{"approve", "reject"}) {
+		t.Errorf("unexpected actions: %v", actions)
+	}
+
+	assertJSON(t, []byte(bodies[0]), `{"applicant": "alice"}`)
+	assertJSON(t, []byte(bodies[1]), `{"applicant": "bob", "reason": "群已满"}`)
+
+	if e := em.ApproveGroupApplication(ctx, "g1", ""); e == nil {
+		t.Error("expected error for empty applicant")
+	}
+}
+
+func TestGroupInvitations(t *testing.T) {
+	var query, canceled string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatgroups/g1/invitations", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [
+			{"invitee": "bob", "inviter": "alice", "invited_at": 1710000000000, "expire_at": 1710604800000}
+		]}`))
+	})
+	mux.HandleFunc("DELETE /org/app/chatgroups/g1/invitations/{invitee}", func(w http.ResponseWriter, r *http.Request) {
+		canceled = r.PathValue("invitee")
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]bool{"result": true}})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	invitations, e := em.GetGroupInvitations(ctx, "g1", 1, 50)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if query != "pagenum=1&pagesize=50" {
+		t.Errorf("unexpected query: %s", query)
+	}
+
+	want := &GroupInvitation{
+		Invitee:   "bob",
+		Inviter:   "alice",
+		InvitedAt: time.UnixMilli(1710000000000),
+		ExpireAt:  time.UnixMilli(1710604800000),
+	}
+	if len(invitations) != 1 || !reflect.DeepEqual(invitations[0], want) {
+		t.Errorf("unexpected invitations: %+v", invitations)
+	}
+
+	if e := em.CancelGroupInvitation(ctx, "g1", "bob"); e != nil {
+		t.Fatal(e)
+	}
+
+	if canceled != "bob" {
+		t.Errorf("unexpected canceled invitee: %s", canceled)
+	}
+
+	if e := em.CancelGroupInvitation(ctx, "g1", ""); e == nil {
+		t.Error("expected error for empty invitee")
+	}
+}
+
+func TestGetGroupBlacklist(t *testing.T) {
+	var query string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatgroups/g1/blocks/users", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [
+			{"member": "spammer", "reason": "广告", "blocked_at": 1710000000000},
+			{"member": "troll", "blocked_at": 1710000100000}
+		]}`))
+	})
+
+	em := newTestEasemob(t, mux)
+
+	entries, e := em.GetGroupBlacklist(context.Background(), "g1", 3, 20)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if query != "pagenum=3&pagesize=20" {
+		t.Errorf("unexpected query: %s", query)
+	}
+
+	want := GroupBlacklist{
+		{Member: "spammer", Reason: "广告", BlockedAt: time.UnixMilli(1710000000000)},
+		{Member: "troll", BlockedAt: time.UnixMilli(1710000100000)},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("unexpected blacklist: %+v", entries)
+	}
+
+	if _, e := em.GetGroupBlacklist(context.Background(), "", 1, 20); e == nil {
+		t.Error("expected error for empty group id")
+	}
+}
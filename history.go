@@ -0,0 +1,326 @@
+package easemob
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+	"uw/ureq"
+)
+
+// DefaultHistoryLocation 国内集群生成历史消息文件所使用的时区 (UTC+8)
+var DefaultHistoryLocation = time.FixedZone("UTC+8", 8*60*60)
+
+// historyHourLayout 历史消息文件的时间格式 yyyyMMddHH
+const historyHourLayout = "2006010215"
+
+// ErrHistoryNotReady 历史消息文件尚未生成, 通常需要等待该小时结束后一段时间再重试
+var ErrHistoryNotReady = errors.New("history file not ready")
+
+type HistoryFile struct {
+	Hour      time.Time // 文件对应的小时
+	URL       string    // 下载地址
+	ExpiresAt time.Time // 下载地址过期时间, 服务端未返回时为零值
+}
+
+type historyFileResp struct {
+	Url     string `json:"url"`
+	Expires int64  `json:"expires"` // 下载地址过期时间, Unix 时间戳, 单位为毫秒
+}
+
+// historyHour 将时间转换为集群时区下的 yyyyMMddHH
+func (em *Easemob) historyHour(hour time.Time) string {
+	em.mu.RLock()
+	loc := em.historyLocation
+	em.mu.RUnlock()
+
+	return hour.In(loc).Format(historyHourLayout)
+}
+
+// GetHistoryFileURL 获取某一小时的历史消息文件下载地址
+// 小时按 SetHistoryLocation 设置的时区 (默认 DefaultHistoryLocation) 划分,
+// 文件尚未生成时返回的错误满足 errors.Is(e, ErrHistoryNotReady)
+// hour: 文件对应的小时, 分钟及以下的部分会被忽略
+func (em *Easemob) GetHistoryFileURL(ctx context.Context, hour time.Time) (*HistoryFile, error) {
+	if hour.IsZero() {
+		return nil, errors.New("get history file url error: hour is zero")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("chatmessages", em.historyHour(hour))).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get history file url error: %w", e)
+	}
+
+	if !res.OK() {
+		e := newResponseError("get history file url", res)
+		if errors.Is(e, ErrNotFound) {
+			return nil, fmt.Errorf("%w: %w", ErrHistoryNotReady, e)
+		}
+
+		return nil, e
+	}
+
+	resp := &RespCommon[[]*historyFileResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get history file url error: %w", e)
+	}
+
+	if len(resp.Data) < 1 || len(resp.Data[0].Url) < 1 {
+		return nil, fmt.Errorf("get history file url error: %w", ErrHistoryNotReady)
+	}
+
+	hf := &HistoryFile{
+		Hour: hour.Truncate(time.Hour),
+		URL:  resp.Data[0].Url,
+	}
+
+	// 未返回过期时间时保持零值, 下载时不做过期检查
+	if resp.Data[0].Expires > 0 {
+		hf.ExpiresAt = time.UnixMilli(resp.Data[0].Expires)
+	}
+
+	return hf, nil
+}
+
+// HistoryDownloadMaxResume 下载历史消息文件时连接中断后使用 Range 续传的最大次数
+const HistoryDownloadMaxResume = 3
+
+// historyFileReader 历史消息文件的原始 (压缩) 数据流, 连接中断时使用 Range 从已读取的位置续传
+type historyFileReader struct {
+	ctx    context.Context
+	em     *Easemob
+	url    string
+	body   io.ReadCloser
+	offset int64 // 已读取的字节数
+	ranged bool  // 存储服务是否支持 Range 续传
+	resume int   // 已续传的次数
+}
+
+func (r *historyFileReader) open() error {
+	c, e := r.em.GetBaseClient(r.ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	c = c.Get(r.url)
+	if r.offset > 0 {
+		c = c.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-")
+	}
+
+	res, e := r.em.end(c)
+	if e != nil {
+		return e
+	}
+
+	switch {
+	case r.offset > 0 && res.StatusCode == http.StatusPartialContent:
+	case r.offset == 0 && res.OK():
+		r.ranged = res.Header.Get("Accept-Ranges") == "bytes"
+	default:
+		_ = res.Body.Close()
+		return newResponseError("download history file", res)
+	}
+
+	r.body = res.Body
+	return nil
+}
+
+func (r *historyFileReader) Read(p []byte) (int, error) {
+	for {
+		n, e := r.body.Read(p)
+		r.offset += int64(n)
+		if e == nil || errors.Is(e, io.EOF) || n > 0 {
+			return n, e
+		}
+
+		if !r.ranged || r.resume >= HistoryDownloadMaxResume || r.ctx.Err() != nil {
+			return n, e
+		}
+
+		_ = r.body.Close()
+		r.resume++
+		if e := r.open(); e != nil {
+			return 0, e
+		}
+	}
+}
+
+func (r *historyFileReader) Close() error {
+	return r.body.Close()
+}
+
+// DownloadHistoryFile 流式下载历史消息文件并解压, 解压后的数据写入 w, 不会在内存中缓存整个文件
+// 存储服务支持 Range 时, 连接中断后会从中断的位置续传, 最多续传 HistoryDownloadMaxResume 次
+// hf: GetHistoryFileURL 返回的文件信息, w: 解压后数据的写入目标
+func (em *Easemob) DownloadHistoryFile(ctx context.Context, hf *HistoryFile, w io.Writer) error {
+	if hf == nil || len(hf.URL) < 1 {
+		return errors.New("download history file error: url is empty")
+	}
+
+	if !hf.ExpiresAt.IsZero() && hf.ExpiresAt.Before(time.Now()) {
+		return errors.New("download history file error: url is expired")
+	}
+
+	r := &historyFileReader{ctx: ctx, em: em, url: hf.URL}
+	if e := r.open(); e != nil {
+		return fmt.Errorf("download history file error: %w", e)
+	}
+	defer r.Close()
+
+	zr, e := gzip.NewReader(r)
+	if e != nil {
+		return fmt.Errorf("download history file error: %w", e)
+	}
+	defer zr.Close()
+
+	if _, e = io.Copy(w, zr); e != nil {
+		return fmt.Errorf("download history file error: %w", e)
+	}
+
+	return nil
+}
+
+type HistoryMessage struct {
+	MsgId     string          `json:"msg_id"`    // 消息 ID
+	Timestamp int64           `json:"timestamp"` // 消息发送时间, Unix 时间戳, 单位为毫秒
+	Direction string          `json:"direction"` // 消息方向, 例如 outgoing
+	From      string          `json:"from"`      // 发送方
+	To        string          `json:"to"`        // 接收方 (用户, 群组或聊天室 ID)
+	ChatType  ChatType        `json:"chat_type"` // 会话类型
+	Payload   json.RawMessage `json:"payload"`   // 消息内容 (bodies 和 ext)
+}
+
+// Time 消息发送时间
+func (m *HistoryMessage) Time() time.Time {
+	return time.UnixMilli(m.Timestamp)
+}
+
+// DecodeHistoryMessages 逐条解析解压后的历史消息文件 (每行一条 JSON 消息记录)
+// fn 返回错误时停止解析并返回该错误
+// r: 解压后的历史消息文件, fn: 每条消息的回调
+func DecodeHistoryMessages(r io.Reader, fn func(HistoryMessage) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var msg HistoryMessage
+		if e := dec.Decode(&msg); e != nil {
+			if errors.Is(e, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("decode history messages error: %w", e)
+		}
+
+		if e := fn(msg); e != nil {
+			return e
+		}
+	}
+}
+
+type HistoryIterOptions struct {
+	Retries        int                  // 文件尚未生成时的重试次数
+	RetryInterval  time.Duration        // 重试间隔
+	FailOnNotReady bool                 // 重试后仍未生成时返回错误, 默认跳过该小时
+	Progress       func(hour time.Time) // 开始处理每个小时前的回调, 用于报告进度
+}
+
+// HistoryIterOption IterateHistory 的可选项
+type HistoryIterOption func(o *HistoryIterOptions)
+
+// WithHistoryRetry 文件尚未生成时每隔 interval 重试, 最多 retries 次
+func WithHistoryRetry(retries int, interval time.Duration) HistoryIterOption {
+	return func(o *HistoryIterOptions) {
+		o.Retries = retries
+		o.RetryInterval = interval
+	}
+}
+
+// WithHistoryFailOnNotReady 文件重试后仍未生成时返回 ErrHistoryNotReady, 而不是跳过该小时
+func WithHistoryFailOnNotReady() HistoryIterOption {
+	return func(o *HistoryIterOptions) {
+		o.FailOnNotReady = true
+	}
+}
+
+// WithHistoryProgress 设置进度回调, 开始处理每个小时前调用
+func WithHistoryProgress(fn func(hour time.Time)) HistoryIterOption {
+	return func(o *HistoryIterOptions) {
+		o.Progress = fn
+	}
+}
+
+// IterateHistory 依次获取 [from, to) 范围内每个小时的历史消息文件, 下载解压并逐条回调 fn
+// 文件尚未生成的小时默认跳过, 可以通过 WithHistoryRetry 和 WithHistoryFailOnNotReady 调整,
+// fn 返回错误时立即停止并返回该错误
+// from: 开始时间, to: 结束时间, fn: 每条消息的回调, opts: 可选项
+func (em *Easemob) IterateHistory(ctx context.Context, from, to time.Time, fn func(HistoryMessage) error, opts ...HistoryIterOption) error {
+	if !from.Before(to) {
+		return errors.New("iterate history error: from must be before to")
+	}
+
+	o := &HistoryIterOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	for hour := from.Truncate(time.Hour); hour.Before(to); hour = hour.Add(time.Hour) {
+		if o.Progress != nil {
+			o.Progress(hour)
+		}
+
+		hf, e := em.getHistoryFileWithRetry(ctx, hour, o)
+		if errors.Is(e, ErrHistoryNotReady) && !o.FailOnNotReady {
+			continue
+		}
+		if e != nil {
+			return fmt.Errorf("iterate history error: %w", e)
+		}
+
+		if e := em.iterateHistoryFile(ctx, hf, fn); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (em *Easemob) getHistoryFileWithRetry(ctx context.Context, hour time.Time, o *HistoryIterOptions) (*HistoryFile, error) {
+	for i := 0; ; i++ {
+		hf, e := em.GetHistoryFileURL(ctx, hour)
+		if !errors.Is(e, ErrHistoryNotReady) || i >= o.Retries {
+			return hf, e
+		}
+
+		select {
+		case <-time.After(o.RetryInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// iterateHistoryFile 边下载边解析, 不会在内存中缓存整个文件
+func (em *Easemob) iterateHistoryFile(ctx context.Context, hf *HistoryFile, fn func(HistoryMessage) error) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		_ = pw.CloseWithError(em.DownloadHistoryFile(ctx, hf, pw))
+	}()
+
+	e := DecodeHistoryMessages(pr, fn)
+	_ = pr.CloseWithError(e)
+	return e
+}
@@ -0,0 +1,346 @@
+package easemob
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetHistoryFileURL(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatmessages/{hour}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("hour") {
+		case "2024031000":
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"data": []map[string]interface{}{{"url": "https://oss.example.com/2024031000.gz", "expires": expires.UnixMilli()}},
+			})
+		case "2024031001":
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"data": []map[string]interface{}{{"url": "https://oss.example.com/2024031001.gz"}},
+			})
+		case "2024031002":
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": []interface{}{}})
+		default:
+			writeError(w, http.StatusNotFound, "storage_object_not_found", "history file not found")
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	// 16:30 UTC 对应 UTC+8 的次日 00 点
+	hour := time.Date(2024, 3, 9, 16, 30, 0, 0, time.UTC)
+
+	hf, e := em.GetHistoryFileURL(ctx, hour)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if hf.URL != "https://oss.example.com/2024031000.gz" || !hf.ExpiresAt.Equal(expires) ||
+		!hf.Hour.Equal(time.Date(2024, 3, 9, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected file: %+v", hf)
+	}
+
+	// 未返回过期时间时不应被当作已过期
+	hf, e = em.GetHistoryFileURL(ctx, hour.Add(time.Hour))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if hf.URL != "https://oss.example.com/2024031001.gz" || !hf.ExpiresAt.IsZero() {
+		t.Errorf("unexpected file without expires: %+v", hf)
+	}
+
+	for _, h := range []time.Time{hour.Add(2 * time.Hour), hour.Add(3 * time.Hour)} {
+		if _, e := em.GetHistoryFileURL(ctx, h); !errors.Is(e, ErrHistoryNotReady) {
+			t.Errorf("%s: expected not ready, got %v", h, e)
+		}
+	}
+}
+
+func TestGetHistoryFileURLLocation(t *testing.T) {
+	var got []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatmessages/{hour}", func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.PathValue("hour"))
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]interface{}{{"url": "https://oss.example.com/file.gz"}},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+	hour := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	if _, e := em.GetHistoryFileURL(context.Background(), hour); e != nil {
+		t.Fatal(e)
+	}
+
+	em.SetHistoryLocation(time.UTC)
+	if _, e := em.GetHistoryFileURL(context.Background(), hour); e != nil {
+		t.Fatal(e)
+	}
+
+	if len(got) != 2 || got[0] != "2025010107" || got[1] != "2024123123" {
+		t.Errorf("unexpected hours: %v", got)
+	}
+}
+
+const historyFixture = `{"msg_id":"m1","timestamp":1710000000000,"direction":"outgoing","from":"alice","to":"bob","chat_type":"chat","payload":{"bodies":[{"type":"txt","msg":"hi"}]}}
+{"msg_id":"m2","timestamp":1710000001000,"direction":"outgoing","from":"bob","to":"g1","chat_type":"groupchat","payload":{"bodies":[{"type":"txt","msg":"hello"}],"ext":{"k":"v"}}}
+`
+
+// gzipBytes 压缩 data, 用于构造历史消息文件
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if _, e := zw.Write([]byte(data)); e != nil {
+		t.Fatal(e)
+	}
+
+	if e := zw.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	return buf.Bytes()
+}
+
+func TestDownloadHistoryFile(t *testing.T) {
+	data := gzipBytes(t, historyFixture)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /files/history.gz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("download should not send the access token")
+		}
+		_, _ = w.Write(data)
+	})
+
+	em := newTestEasemob(t, mux)
+
+	// 未返回过期时间的文件可以正常下载
+	out := &bytes.Buffer{}
+	if e := em.DownloadHistoryFile(context.Background(), &HistoryFile{URL: fileURL(em, "/files/history.gz")}, out); e != nil {
+		t.Fatal(e)
+	}
+
+	if out.String() != historyFixture {
+		t.Errorf("unexpected content: %q", out.String())
+	}
+
+	var msgs []HistoryMessage
+	if e := DecodeHistoryMessages(out, func(m HistoryMessage) error {
+		msgs = append(msgs, m)
+		return nil
+	}); e != nil {
+		t.Fatal(e)
+	}
+
+	if len(msgs) != 2 || msgs[0].MsgId != "m1" || msgs[1].ChatType != ChatTypeGroupChat ||
+		!msgs[1].Time().Equal(time.UnixMilli(1710000001000)) {
+		t.Errorf("unexpected messages: %+v", msgs)
+	}
+
+	expired := &HistoryFile{URL: fileURL(em, "/files/history.gz"), ExpiresAt: time.Now().Add(-time.Minute)}
+	if e := em.DownloadHistoryFile(context.Background(), expired, io.Discard); e == nil {
+		t.Error("expected error for expired url")
+	}
+}
+
+func TestDownloadHistoryFileStreaming(t *testing.T) {
+	line := `{"msg_id":"m","timestamp":1710000000000,"chat_type":"chat","payload":{}}` + "\n"
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	_, _ = zw.Write([]byte(strings.Repeat(line, 100)))
+	_ = zw.Flush()
+	head := append([]byte(nil), buf.Bytes()...)
+
+	buf.Reset()
+	_, _ = zw.Write([]byte(strings.Repeat(line, 100)))
+	_ = zw.Close()
+	tail := buf.Bytes()
+
+	// 服务端先发送前半部分, 直到客户端解压出数据后才发送剩余部分
+	received := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /files/history.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(head)
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Error("client did not receive data before the download finished")
+		}
+		_, _ = w.Write(tail)
+	})
+
+	em := newTestEasemob(t, mux)
+
+	var once sync.Once
+	n := 0
+	w := writerFunc(func(p []byte) (int, error) {
+		once.Do(func() { close(received) })
+		n += len(p)
+		return len(p), nil
+	})
+
+	if e := em.DownloadHistoryFile(context.Background(), &HistoryFile{URL: fileURL(em, "/files/history.gz")}, w); e != nil {
+		t.Fatal(e)
+	}
+
+	if n != 200*len(line) {
+		t.Errorf("unexpected size: %d", n)
+	}
+}
+
+func TestDownloadHistoryFileResume(t *testing.T) {
+	data := gzipBytes(t, historyFixture)
+	half := len(data) / 2
+
+	var ranges []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /files/history.gz", func(w http.ResponseWriter, r *http.Request) {
+		rg := r.Header.Get("Range")
+		ranges = append(ranges, rg)
+
+		if rg == "" {
+			// 只发送一半数据后断开连接
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			_, _ = w.Write(data[:half])
+			w.(http.Flusher).Flush()
+
+			conn, _, e := w.(http.Hijacker).Hijack()
+			if e != nil {
+				t.Error(e)
+				return
+			}
+			_ = conn.Close()
+			return
+		}
+
+		if rg != "bytes="+strconv.Itoa(half)+"-" {
+			t.Errorf("unexpected range: %q", rg)
+		}
+
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", half, len(data)-1, len(data)))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(data[half:])
+	})
+
+	em := newTestEasemob(t, mux)
+
+	out := &bytes.Buffer{}
+	if e := em.DownloadHistoryFile(context.Background(), &HistoryFile{URL: fileURL(em, "/files/history.gz")}, out); e != nil {
+		t.Fatal(e)
+	}
+
+	if out.String() != historyFixture || len(ranges) != 2 {
+		t.Errorf("unexpected result: %d requests, %q", len(ranges), out.String())
+	}
+}
+
+// fileURL 测试服务器上不属于 Easemob API 的地址, 例如存储服务的下载地址
+func fileURL(em *Easemob, p string) string {
+	return em.GetURL("").ResolveReference(&url.URL{Path: p}).String()
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestIterateHistory(t *testing.T) {
+	files := map[string]string{
+		"2024031000": `{"msg_id":"a1","timestamp":1710000000000,"chat_type":"chat","payload":{}}` + "\n" +
+			`{"msg_id":"a2","timestamp":1710000001000,"chat_type":"chat","payload":{}}` + "\n",
+		"2024031002": `{"msg_id":"c1","timestamp":1710007200000,"chat_type":"groupchat","payload":{}}` + "\n",
+	}
+
+	var mu sync.Mutex
+	lookups := map[string]int{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatmessages/{hour}", func(w http.ResponseWriter, r *http.Request) {
+		hour := r.PathValue("hour")
+		mu.Lock()
+		lookups[hour]++
+		mu.Unlock()
+
+		if _, ok := files[hour]; !ok {
+			writeError(w, http.StatusNotFound, "storage_object_not_found", "history file not found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]interface{}{{"url": "http://" + r.Host + "/files/" + hour + ".gz"}},
+		})
+	})
+	mux.HandleFunc("GET /files/{name}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(gzipBytes(t, files[strings.TrimSuffix(r.PathValue("name"), ".gz")]))
+	})
+
+	em := newTestEasemob(t, mux)
+	from := time.Date(2024, 3, 10, 0, 15, 0, 0, DefaultHistoryLocation)
+	to := from.Add(3 * time.Hour).Truncate(time.Hour)
+
+	var ids []string
+	var hours []time.Time
+	e := em.IterateHistory(context.Background(), from, to, func(m HistoryMessage) error {
+		ids = append(ids, m.MsgId)
+		return nil
+	}, WithHistoryRetry(2, time.Millisecond), WithHistoryProgress(func(hour time.Time) {
+		hours = append(hours, hour)
+	}))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// 01 点的文件未生成, 重试后跳过
+	if strings.Join(ids, ",") != "a1,a2,c1" {
+		t.Errorf("unexpected messages: %v", ids)
+	}
+
+	if len(hours) != 3 || !hours[0].Equal(from.Truncate(time.Hour)) || !hours[2].Equal(from.Truncate(time.Hour).Add(2*time.Hour)) {
+		t.Errorf("unexpected progress: %v", hours)
+	}
+
+	if lookups["2024031001"] != 3 || lookups["2024031000"] != 1 {
+		t.Errorf("unexpected lookups: %v", lookups)
+	}
+
+	// 未生成时返回错误
+	e = em.IterateHistory(context.Background(), from, to, func(m HistoryMessage) error {
+		return nil
+	}, WithHistoryFailOnNotReady())
+	if !errors.Is(e, ErrHistoryNotReady) {
+		t.Errorf("expected not ready, got %v", e)
+	}
+
+	// 回调返回错误时立即停止
+	stop := errors.New("stop")
+	n := 0
+	e = em.IterateHistory(context.Background(), from, to, func(m HistoryMessage) error {
+		n++
+		return stop
+	})
+	if !errors.Is(e, stop) || n != 1 {
+		t.Errorf("expected early exit after one message, got %d messages and %v", n, e)
+	}
+}
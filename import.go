@@ -0,0 +1,121 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"uw/ureq"
+)
+
+// importMessageMinTimestamp 毫秒时间戳的下限 (2001-09-09), 小于该值的时间戳通常是误传的秒级时间戳
+const importMessageMinTimestamp = 1e12
+
+type ImportMessage struct {
+	From               string      // 发送方
+	Target             string      // 接收方, 单聊为用户 ID, 群聊为群组 ID
+	Body               MessageBody // 消息内容
+	Timestamp          int64       // 原始发送时间, Unix 时间戳, 单位为毫秒, 为 0 时为当前时间
+	IsAckRead          bool        // 是否标记为已读
+	NeedDownloadSource bool        // 是否需要 Easemob 下载并转存附件 (图片, 音视频和文件)
+}
+
+type ImportMessageResult struct {
+	MsgId string `json:"msg_id"` // 导入后的消息 ID
+}
+
+type importMessageReq struct {
+	From               string      `json:"from"`
+	Target             string      `json:"target"`
+	Type               string      `json:"type"`
+	Body               MessageBody `json:"body"`
+	MsgTimestamp       int64       `json:"msg_timestamp"`
+	IsAckRead          bool        `json:"is_ack_read"`
+	NeedDownloadSource bool        `json:"need_download_source"`
+}
+
+// ImportChatMessage 导入单聊历史消息, 保留原始发送时间, 用于从其他服务迁移消息
+// msg.Timestamp 必须是毫秒时间戳, 为 0 时使用当前时间
+// msg: 待导入的消息
+func (em *Easemob) ImportChatMessage(ctx context.Context, msg ImportMessage) (*ImportMessageResult, error) {
+	return em.importMessage(ctx, "import chat message", "messages/users/import", &msg)
+}
+
+// ImportGroupMessage 导入群聊历史消息, 保留原始发送时间, 用于从其他服务迁移消息
+// msg.Target 会被 groupID 覆盖, msg.From 不能为空, msg.Timestamp 必须是毫秒时间戳, 为 0 时使用当前时间
+// groupID: 群组 ID, msg: 待导入的消息
+func (em *Easemob) ImportGroupMessage(ctx context.Context, groupID string, msg ImportMessage) (*ImportMessageResult, error) {
+	if len(groupID) < 1 {
+		return nil, errors.New("import group message error: group id is empty")
+	}
+
+	if len(msg.From) < 1 {
+		return nil, errors.New("import group message error: from is empty")
+	}
+
+	msg.Target = groupID
+	return em.importMessage(ctx, "import group message", "messages/chatgroups/import", &msg)
+}
+
+func (em *Easemob) importMessage(ctx context.Context, op, subPath string, msg *ImportMessage) (*ImportMessageResult, error) {
+	if len(msg.Target) < 1 {
+		return nil, fmt.Errorf("%s error: target is empty", op)
+	}
+
+	if msg.Body == nil {
+		return nil, fmt.Errorf("%s error: body is nil", op)
+	}
+
+	timestamp := msg.Timestamp
+	switch {
+	case timestamp == 0:
+		timestamp = time.Now().UnixMilli()
+	case timestamp < importMessageMinTimestamp:
+		return nil, fmt.Errorf("%s error: timestamp %d is not in milliseconds", op, timestamp)
+	}
+
+	body, e := em.prepareMessageBody(msg.Body)
+	if e != nil {
+		return nil, fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if e := em.getEndpointLimiter(ctx, LimiterImportMessages); e != nil {
+		return nil, e
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(subPath).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&importMessageReq{
+			From:               msg.From,
+			Target:             msg.Target,
+			Type:               body.MessageType(),
+			Body:               body,
+			MsgTimestamp:       timestamp,
+			IsAckRead:          msg.IsAckRead,
+			NeedDownloadSource: msg.NeedDownloadSource,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError(op, res)
+	}
+
+	resp := &RespCommon[*ImportMessageResult]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if resp.Data == nil {
+		return nil, errors.New(op + " error: empty data")
+	}
+
+	return resp.Data, nil
+}
@@ -0,0 +1,203 @@
+package easemob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// importRecorder 记录导入消息接口收到的请求体
+type importRecorder struct {
+	paths  []string
+	bodies [][]byte
+}
+
+func (rec *importRecorder) handler(t *testing.T) http.Handler {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		body, e := io.ReadAll(r.Body)
+		if e != nil {
+			t.Error(e)
+		}
+
+		rec.paths = append(rec.paths, r.URL.Path)
+		rec.bodies = append(rec.bodies, body)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]string{"msg_id": "imported-" + strconv.Itoa(len(rec.bodies))},
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/messages/users/import", h)
+	mux.HandleFunc("POST /org/app/messages/chatgroups/import", h)
+	return mux
+}
+
+func TestImportChatMessage(t *testing.T) {
+	rec := &importRecorder{}
+	em := newTestEasemob(t, rec.handler(t))
+	ctx := context.Background()
+
+	res, e := em.ImportChatMessage(ctx, ImportMessage{
+		From:      "alice",
+		Target:    "bob",
+		Body:      &TextBody{Msg: "hello"},
+		Timestamp: 1710000000123,
+		IsAckRead: true,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if res.MsgId != "imported-1" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	assertJSON(t, rec.bodies[0], `{
+		"from": "alice",
+		"target": "bob",
+		"type": "txt",
+		"body": {"msg": "hello"},
+		"msg_timestamp": 1710000000123,
+		"is_ack_read": true,
+		"need_download_source": false
+	}`)
+
+	_, e = em.ImportChatMessage(ctx, ImportMessage{
+		From:               "alice",
+		Target:             "bob",
+		Body:               &ImageBody{Filename: "a.jpg", Url: "https://example.com/a.jpg", Size: &ImageSize{Width: 640, Height: 480}},
+		Timestamp:          1710000000456,
+		NeedDownloadSource: true,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, rec.bodies[1], `{
+		"from": "alice",
+		"target": "bob",
+		"type": "img",
+		"body": {"filename": "a.jpg", "url": "https://example.com/a.jpg", "size": {"width": 640, "height": 480}},
+		"msg_timestamp": 1710000000456,
+		"is_ack_read": false,
+		"need_download_source": true
+	}`)
+
+	for _, v := range rec.paths {
+		if v != "/org/app/messages/users/import" {
+			t.Errorf("unexpected path: %s", v)
+		}
+	}
+}
+
+func TestImportChatMessageTimestamp(t *testing.T) {
+	rec := &importRecorder{}
+	em := newTestEasemob(t, rec.handler(t))
+	ctx := context.Background()
+
+	// 时间戳为 0 时使用当前时间
+	before := time.Now().UnixMilli()
+	if _, e := em.ImportChatMessage(ctx, ImportMessage{From: "alice", Target: "bob", Body: &TextBody{Msg: "hi"}}); e != nil {
+		t.Fatal(e)
+	}
+	after := time.Now().UnixMilli()
+
+	req := &importMessageReq{Body: &TextBody{}}
+	if e := json.Unmarshal(rec.bodies[0], req); e != nil {
+		t.Fatal(e)
+	}
+
+	if req.MsgTimestamp < before || req.MsgTimestamp > after {
+		t.Errorf("timestamp %d not in [%d, %d]", req.MsgTimestamp, before, after)
+	}
+
+	// 秒级时间戳不会被发送
+	if _, e := em.ImportChatMessage(ctx, ImportMessage{From: "alice", Target: "bob", Body: &TextBody{Msg: "hi"}, Timestamp: 1710000000}); e == nil {
+		t.Error("expected error for timestamp in seconds")
+	}
+
+	if _, e := em.ImportChatMessage(ctx, ImportMessage{From: "alice", Target: "bob"}); e == nil {
+		t.Error("expected error for nil body")
+	}
+
+	if len(rec.bodies) != 1 {
+		t.Errorf("invalid messages should not be sent: %d requests", len(rec.bodies))
+	}
+}
+
+func TestImportGroupMessage(t *testing.T) {
+	rec := &importRecorder{}
+	em := newTestEasemob(t, rec.handler(t))
+
+	// Target 会被 groupID 覆盖
+	_, e := em.ImportGroupMessage(context.Background(), "g1", ImportMessage{
+		From:      "alice",
+		Target:    "ignored",
+		Body:      &TextBody{Msg: "hello group"},
+		Timestamp: 1710000000123,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if len(rec.paths) != 1 || rec.paths[0] != "/org/app/messages/chatgroups/import" {
+		t.Fatalf("unexpected paths: %v", rec.paths)
+	}
+
+	assertJSON(t, rec.bodies[0], `{
+		"from": "alice",
+		"target": "g1",
+		"type": "txt",
+		"body": {"msg": "hello group"},
+		"msg_timestamp": 1710000000123,
+		"is_ack_read": false,
+		"need_download_source": false
+	}`)
+}
+
+func TestImportGroupMessageValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	ctx := context.Background()
+
+	if _, e := em.ImportGroupMessage(ctx, "", ImportMessage{From: "alice", Body: &TextBody{Msg: "hi"}}); e == nil {
+		t.Error("expected error for empty group id")
+	}
+
+	if _, e := em.ImportGroupMessage(ctx, "g1", ImportMessage{Body: &TextBody{Msg: "hi"}}); e == nil {
+		t.Error("expected error for empty from")
+	}
+}
+
+func TestImportMessageLimiter(t *testing.T) {
+	rec := &importRecorder{}
+	em := newTestEasemob(t, rec.handler(t))
+	em.SetEndpointLimiter(LimiterImportMessages, 1, time.Hour)
+
+	msg := ImportMessage{From: "alice", Body: &TextBody{Msg: "hi"}, Timestamp: 1710000000000}
+	if _, e := em.ImportGroupMessage(context.Background(), "g1", msg); e != nil {
+		t.Fatal(e)
+	}
+
+	// 导入接口的配额已用完, 单聊和群聊导入都需要等待
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, e := em.ImportGroupMessage(ctx, "g1", msg); !errors.Is(e, context.DeadlineExceeded) {
+		t.Errorf("expected group import to wait for the limiter, got %v", e)
+	}
+
+	msg.Target = "bob"
+	if _, e := em.ImportChatMessage(ctx, msg); !errors.Is(e, context.DeadlineExceeded) {
+		t.Errorf("expected chat import to wait for the limiter, got %v", e)
+	}
+
+	if len(rec.bodies) != 1 {
+		t.Errorf("unexpected request count: %d", len(rec.bodies))
+	}
+}
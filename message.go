@@ -0,0 +1,635 @@
+package easemob
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"uw/ureq"
+)
+
+// HeaderRequestID 幂等键请求头
+const HeaderRequestID = "X-Easemob-Request-ID"
+
+// RouteOnline 只投递给在线用户, 离线用户不会收到也不会存储离线消息
+const RouteOnline = "ROUTE_ONLINE"
+
+type MessageOptions struct {
+	// 幂等键, 非空时通过 X-Easemob-Request-ID 请求头发送, 用于避免网络重试导致的重复投递
+	// 每次发送消息都必须使用全局唯一的 UUID, 由调用方负责生成 (可使用 NewIdempotencyKey)
+	IdempotencyKey string
+
+	From       string                 // 发送方, 非空时覆盖发送方法的 from 参数
+	Ext        map[string]interface{} // 消息扩展字段
+	RouteType  string                 // 路由类型, 例如 RouteOnline
+	SyncDevice bool                   // 是否将消息同步到发送方的其他设备
+
+	ChatroomMsgLevel ChatroomMsgLevel // 聊天室消息优先级, 仅对聊天室消息有效
+
+	// 消息投递优先级, PriorityHigh 的消息跳过普通队列优先投递
+	// 注意: 高优先级消息单独限流 (默认 100 条/秒, 以套餐为准) 并额外计费, 只应用于验证码, 告警等时效性强的消息
+	Priority MessagePriority
+
+	NeedGroupAck bool // 是否需要群组消息已读回执, 仅对群聊消息有效, 参见 GetGroupMessageReadUsers
+
+	TranslateTo []string // 消息翻译的目标语言, 接收方会同时收到译文, 必须在 TranslationLanguages 中
+}
+
+// WithTranslation 发送时将消息翻译为指定语言, 仅对文本消息有效
+func WithTranslation(targetLangs ...string) MessageOption {
+	return func(o *MessageOptions) {
+		o.TranslateTo = append(o.TranslateTo, targetLangs...)
+	}
+}
+
+// WithGroupAck 请求群组消息已读回执, 仅对群聊消息有效
+func WithGroupAck() MessageOption {
+	return func(o *MessageOptions) {
+		o.NeedGroupAck = true
+	}
+}
+
+// MessagePriority 消息投递优先级
+type MessagePriority string
+
+const (
+	PriorityNormal MessagePriority = "normal" // 普通 (默认)
+	PriorityHigh   MessagePriority = "high"   // 高优先级, 单独限流并额外计费
+)
+
+// WithPriority 设置消息投递优先级, 参见 MessageOptions.Priority
+func WithPriority(priority MessagePriority) MessageOption {
+	return func(o *MessageOptions) {
+		o.Priority = priority
+	}
+}
+
+// priority 请求中的 priority 字段, 只有高优先级时发送
+func (o *MessageOptions) priority() MessagePriority {
+	if o.Priority == PriorityHigh {
+		return PriorityHigh
+	}
+
+	return ""
+}
+
+// ChatroomMsgLevel 聊天室消息优先级, 消息量大 (例如弹幕) 时服务端优先投递高优先级消息
+type ChatroomMsgLevel string
+
+const (
+	ChatroomMsgLevelHigh   ChatroomMsgLevel = "high"   // 高
+	ChatroomMsgLevelNormal ChatroomMsgLevel = "normal" // 普通 (默认)
+	ChatroomMsgLevelLow    ChatroomMsgLevel = "low"    // 低
+)
+
+// WithChatroomMsgLevel 设置聊天室消息优先级
+func WithChatroomMsgLevel(level ChatroomMsgLevel) MessageOption {
+	return func(o *MessageOptions) {
+		o.ChatroomMsgLevel = level
+	}
+}
+
+// MessageOption 发送消息的可选项, 按传入顺序依次生效
+// 同一字段的选项多次设置时以最后一次为准, WithExt 除外 (合并)
+type MessageOption func(o *MessageOptions)
+
+// WithIdempotencyKey 设置幂等键
+func WithIdempotencyKey(key string) MessageOption {
+	return func(o *MessageOptions) {
+		o.IdempotencyKey = key
+	}
+}
+
+// WithExt 设置消息扩展字段
+// 多次调用时合并所有扩展字段, 相同的键以后设置的为准, 可以直接传入 Ext
+func WithExt(ext map[string]interface{}) MessageOption {
+	return func(o *MessageOptions) {
+		if o.Ext == nil {
+			o.Ext = make(map[string]interface{}, len(ext))
+		}
+
+		for k, v := range ext {
+			o.Ext[k] = v
+		}
+	}
+}
+
+// WithFrom 覆盖消息的发送方, 优先于发送方法的 from 参数
+func WithFrom(from string) MessageOption {
+	return func(o *MessageOptions) {
+		o.From = from
+	}
+}
+
+// WithOnlineOnly 只投递给在线用户
+func WithOnlineOnly() MessageOption {
+	return func(o *MessageOptions) {
+		o.RouteType = RouteOnline
+	}
+}
+
+// WithSyncDevice 将消息同步到发送方的其他设备
+func WithSyncDevice() MessageOption {
+	return func(o *MessageOptions) {
+		o.SyncDevice = true
+	}
+}
+
+func newMessageOptions(opts []MessageOption) *MessageOptions {
+	o := &MessageOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	return o
+}
+
+// apply 将消息选项中的请求头设置到客户端
+func (o *MessageOptions) apply(c *ureq.Client) *ureq.Client {
+	if o == nil {
+		return c
+	}
+
+	if len(o.IdempotencyKey) > 0 {
+		c = c.Set(HeaderRequestID, o.IdempotencyKey)
+	}
+
+	return c
+}
+
+// NewIdempotencyKey 使用 crypto/rand 生成一个随机 UUID (v4) 作为幂等键
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, e := rand.Read(b); e != nil {
+		panic(fmt.Sprintf("new idempotency key error: %s", e))
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+type SearchMessageReq struct {
+	Keyword   string    // 关键词
+	From      string    // 消息发送方, 可选
+	To        string    // 消息接收方 (用户 ID 或群组 ID), 可选
+	Start     time.Time // 开始时间, 可选
+	End       time.Time // 结束时间, 可选
+	MsgType   string    // 消息类型: txt, img, audio, video, file, loc, cmd, custom, 可选
+	Direction string    // 排序方向: asc, desc, 可选
+	Group     bool      // 是否搜索群组消息
+	Limit     int       // 每页数量, 可选
+	Cursor    string    // 分页游标, 首页传空
+}
+
+type SearchMessage struct {
+	MsgId     string          `json:"msg_id"`    // 消息 ID
+	From      string          `json:"from"`      // 消息发送方
+	To        string          `json:"to"`        // 消息接收方
+	ChatType  string          `json:"chat_type"` // 会话类型
+	Timestamp int64           `json:"timestamp"` // 消息发送时间, Unix 时间戳, 单位为毫秒
+	Payload   json.RawMessage `json:"payload"`   // 消息内容
+}
+
+type SearchMessageResult struct {
+	Messages []*SearchMessage `json:"messages"` // 消息列表
+	Cursor   string           `json:"cursor"`   // 下一页游标, 为空表示没有更多数据
+}
+
+// SearchMessages 按关键词搜索历史消息 (需要 Easemob 套餐支持)
+func (em *Easemob) SearchMessages(ctx context.Context, req *SearchMessageReq) (*SearchMessageResult, error) {
+	if req == nil || len(req.Keyword) < 1 {
+		return nil, errors.New("search messages error: keyword is empty")
+	}
+
+	if !req.Start.IsZero() && !req.End.IsZero() && req.End.Before(req.Start) {
+		return nil, errors.New("search messages error: end before start")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL("messages/users")
+	if req.Group {
+		u = em.GetURL("messages/chatgroups")
+	}
+
+	q := url.Values{}
+	q.Set("keyword", req.Keyword)
+	if len(req.From) > 0 {
+		q.Set("from", req.From)
+	}
+	if len(req.To) > 0 {
+		q.Set("to", req.To)
+	}
+	if !req.Start.IsZero() {
+		q.Set("start", strconv.FormatInt(req.Start.UnixMilli(), 10))
+	}
+	if !req.End.IsZero() {
+		q.Set("end", strconv.FormatInt(req.End.UnixMilli(), 10))
+	}
+	if len(req.MsgType) > 0 {
+		q.Set("msg_type", req.MsgType)
+	}
+	if len(req.Direction) > 0 {
+		q.Set("direction", req.Direction)
+	}
+	if req.Limit > 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if len(req.Cursor) > 0 {
+		q.Set("cursor", req.Cursor)
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("search messages error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("search messages", res)
+	}
+
+	resp := &RespCommon[[]*SearchMessage]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("search messages error: %w", e)
+	}
+
+	return &SearchMessageResult{
+		Messages: resp.Data,
+		Cursor:   resp.Cursor,
+	}, nil
+}
+
+type DeliveryStatus struct {
+	UserId    string     // 接收方用户 ID
+	Delivered bool       // 是否已送达
+	Read      bool       // 是否已读
+	ReadAt    *time.Time // 已读时间, 未读时为 nil
+}
+
+type deliveryStatusResp struct {
+	UserId    string `json:"userId"`
+	Delivered bool   `json:"delivered"`
+	Read      bool   `json:"read"`
+	ReadAt    int64  `json:"readAt"` // Unix 时间戳, 单位为毫秒, 未读时为 0
+}
+
+// GetMessageDeliveryStatus 查询消息在各接收方的送达和已读状态
+// msgId: 消息 ID
+func (em *Easemob) GetMessageDeliveryStatus(ctx context.Context, msgId string) ([]*DeliveryStatus, error) {
+	if len(msgId) < 1 {
+		return nil, errors.New("get message delivery status error: msg id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("messages", msgId, "status")).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get message delivery status error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get message delivery status", res)
+	}
+
+	resp := &RespCommon[[]*deliveryStatusResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get message delivery status error: %w", e)
+	}
+
+	statuses := make([]*DeliveryStatus, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		status := &DeliveryStatus{
+			UserId:    v.UserId,
+			Delivered: v.Delivered,
+			Read:      v.Read,
+		}
+
+		if v.ReadAt > 0 {
+			readAt := time.UnixMilli(v.ReadAt)
+			status.ReadAt = &readAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// ChatType 会话类型
+type ChatType string
+
+const (
+	ChatTypeChat      ChatType = "chat"      // 单聊
+	ChatTypeGroupChat ChatType = "groupchat" // 群聊
+	ChatTypeChatroom  ChatType = "chatroom"  // 聊天室
+)
+
+var (
+	ErrMessageNotFound      = errors.New("message not found")              // 消息不存在或已过期
+	ErrRecallWindowExceeded = errors.New("message recall window exceeded") // 超过消息撤回时限
+)
+
+type recallMessageReq struct {
+	MsgId    string   `json:"msg_id"`
+	To       string   `json:"to"`
+	ChatType ChatType `json:"chat_type"`
+	From     string   `json:"from,omitempty"`
+	Force    bool     `json:"force"`
+}
+
+type recallMessageResp struct {
+	Recalled     string `json:"recalled"`     // 撤回成功时为 yes
+	RecallFailed string `json:"recallfailed"` // 撤回失败的原因
+}
+
+// RecallMessage 撤回消息
+// 消息不存在时返回的错误满足 errors.Is(e, ErrMessageNotFound),
+// 超过撤回时限时满足 errors.Is(e, ErrRecallWindowExceeded), 管理员可以设置 force 强制撤回
+// msgID: 消息 ID, to: 接收方 (用户, 群组或聊天室 ID), chatType: 会话类型, from: 发送方, 为空时为 admin, force: 是否强制撤回
+func (em *Easemob) RecallMessage(ctx context.Context, msgID, to string, chatType ChatType, from string, force bool) error {
+	if len(msgID) < 1 || len(to) < 1 {
+		return errors.New("recall message error: msg id or to is empty")
+	}
+
+	switch chatType {
+	case ChatTypeChat, ChatTypeGroupChat, ChatTypeChatroom:
+	default:
+		return fmt.Errorf("recall message error: invalid chat type %q", chatType)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("messages/msg_recall").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&recallMessageReq{
+			MsgId:    msgID,
+			To:       to,
+			ChatType: chatType,
+			From:     from,
+			Force:    force,
+		}))
+	if e != nil {
+		return fmt.Errorf("recall message error: %w", e)
+	}
+
+	if !res.OK() {
+		e := newResponseError("recall message", res)
+		if errors.Is(e, ErrNotFound) {
+			return fmt.Errorf("%w: %w", ErrMessageNotFound, e)
+		}
+
+		return e
+	}
+
+	resp := &RespCommon[*recallMessageResp]{}
+	if e = res.JSON(resp); e != nil {
+		return fmt.Errorf("recall message error: %w", e)
+	}
+
+	if resp.Data == nil || resp.Data.Recalled == "yes" || len(resp.Data.RecallFailed) < 1 {
+		return nil
+	}
+
+	reason := strings.ToLower(resp.Data.RecallFailed)
+	switch {
+	case strings.Contains(reason, "exceed"):
+		return fmt.Errorf("recall message error: %w: %s", ErrRecallWindowExceeded, resp.Data.RecallFailed)
+	case strings.Contains(reason, "not_found"), strings.Contains(reason, "not found"):
+		return fmt.Errorf("recall message error: %w: %s", ErrMessageNotFound, resp.Data.RecallFailed)
+	}
+
+	return fmt.Errorf("recall message error: %s", resp.Data.RecallFailed)
+}
+
+// MessageDeliveryStatus 消息对单个接收方的投递状态
+type MessageDeliveryStatus string
+
+const (
+	MessageUndelivered MessageDeliveryStatus = "UNDELIVERED" // 未送达 (接收方离线, 消息在离线队列中)
+	MessageDelivered   MessageDeliveryStatus = "DELIVERED"   // 已送达
+	MessageRead        MessageDeliveryStatus = "READ"        // 已读, 需要接收方发送已读回执
+)
+
+// GetMessageStatus 查询消息对指定接收方的投递状态
+// 先查询离线消息状态, 已送达时再通过 GetMessageDeliveryStatus 判断是否已读 (服务端不支持时返回 MessageDelivered),
+// 消息不存在时返回的错误满足 errors.Is(e, ErrMessageNotFound), 与 MessageUndelivered 区分
+// username: 接收方用户 ID, msgID: 消息 ID
+func (em *Easemob) GetMessageStatus(ctx context.Context, username, msgID string) (MessageDeliveryStatus, error) {
+	if len(username) < 1 || len(msgID) < 1 {
+		return "", errors.New("get message status error: username or msg id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return "", fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("users", username, "offline_msg_status", msgID)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return "", fmt.Errorf("get message status error: %w", e)
+	}
+
+	if !res.OK() {
+		e := newResponseError("get message status", res)
+		if errors.Is(e, ErrNotFound) {
+			return "", fmt.Errorf("%w: %w", ErrMessageNotFound, e)
+		}
+
+		return "", e
+	}
+
+	resp := &RespCommon[map[string]string]{}
+	if e = res.JSON(resp); e != nil {
+		return "", fmt.Errorf("get message status error: %w", e)
+	}
+
+	switch strings.ToLower(resp.Data[msgID]) {
+	case "undelivered":
+		return MessageUndelivered, nil
+	case "delivered":
+	default:
+		return "", fmt.Errorf("get message status error: %w", ErrMessageNotFound)
+	}
+
+	statuses, e := em.GetMessageDeliveryStatus(ctx, msgID)
+	if errors.Is(e, ErrNotFound) || errors.Is(e, ErrNotSupported) {
+		return MessageDelivered, nil
+	}
+	if e != nil {
+		return "", fmt.Errorf("get message status error: %w", e)
+	}
+
+	for _, v := range statuses {
+		if v.UserId == username && v.Read {
+			return MessageRead, nil
+		}
+	}
+
+	return MessageDelivered, nil
+}
+
+// ErrPinLimitExceeded 会话中置顶的消息数量超过上限
+var ErrPinLimitExceeded = errors.New("pinned message limit exceeded")
+
+type pinMessageReq struct {
+	ConversationId string   `json:"conversationId"`
+	Type           ChatType `json:"type"`
+	MsgId          string   `json:"msgId"`
+	Operator       string   `json:"operator"`
+}
+
+// PinMessage 在群组或聊天室中置顶消息
+// 置顶数量超过上限时返回的错误满足 errors.Is(e, ErrPinLimitExceeded)
+// convType: 会话类型 (groupchat 或 chatroom), convID: 群组或聊天室 ID, msgID: 消息 ID, operator: 操作人
+func (em *Easemob) PinMessage(ctx context.Context, convType ChatType, convID, msgID, operator string) error {
+	return em.pinMessage(ctx, "pin message", "messages/pin", convType, convID, msgID, operator)
+}
+
+// UnpinMessage 在群组或聊天室中取消置顶消息
+// convType: 会话类型 (groupchat 或 chatroom), convID: 群组或聊天室 ID, msgID: 消息 ID, operator: 操作人
+func (em *Easemob) UnpinMessage(ctx context.Context, convType ChatType, convID, msgID, operator string) error {
+	return em.pinMessage(ctx, "unpin message", "messages/unpin", convType, convID, msgID, operator)
+}
+
+func (em *Easemob) pinMessage(ctx context.Context, op, subPath string, convType ChatType, convID, msgID, operator string) error {
+	if len(convID) < 1 || len(msgID) < 1 || len(operator) < 1 {
+		return fmt.Errorf("%s error: conversation id, msg id or operator is empty", op)
+	}
+
+	if convType != ChatTypeGroupChat && convType != ChatTypeChatroom {
+		return fmt.Errorf("%s error: invalid conversation type %q", op, convType)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(subPath).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&pinMessageReq{
+			ConversationId: convID,
+			Type:           convType,
+			MsgId:          msgID,
+			Operator:       operator,
+		}))
+	if e != nil {
+		return fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if !res.OK() {
+		e := newResponseError(op, res)
+
+		re := &ResponseError{}
+		if errors.As(e, &re) && strings.Contains(strings.ToLower(re.Type+re.Description), "limit") {
+			return fmt.Errorf("%w: %w", ErrPinLimitExceeded, e)
+		}
+
+		return e
+	}
+
+	return nil
+}
+
+type PinnedMessage struct {
+	MsgId    string    // 消息 ID
+	Operator string    // 置顶操作人
+	PinnedAt time.Time // 置顶时间
+}
+
+type PinnedMessagesPage struct {
+	Messages []*PinnedMessage // 置顶消息列表
+	Cursor   string           // 下一页游标, 为空表示没有更多数据
+}
+
+type pinnedMessagesResp struct {
+	List []*struct {
+		MsgId    string `json:"msgId"`
+		Operator string `json:"operator"`
+		PinnedAt int64  `json:"pinnedTime"` // Unix 时间戳, 单位为毫秒
+	} `json:"list"`
+	Cursor string `json:"cursor"`
+}
+
+// ListPinnedMessages 分页获取群组或聊天室中置顶的消息
+// convType: 会话类型 (groupchat 或 chatroom), convID: 群组或聊天室 ID, limit: 每页数量, cursor: 分页游标, 首页传空
+func (em *Easemob) ListPinnedMessages(ctx context.Context, convType ChatType, convID string, limit int, cursor string) (*PinnedMessagesPage, error) {
+	if len(convID) < 1 {
+		return nil, errors.New("list pinned messages error: conversation id is empty")
+	}
+
+	if convType != ChatTypeGroupChat && convType != ChatTypeChatroom {
+		return nil, fmt.Errorf("list pinned messages error: invalid conversation type %q", convType)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL("messages/pin")
+	q := url.Values{}
+	q.Set("conversationId", convID)
+	q.Set("type", string(convType))
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if len(cursor) > 0 {
+		q.Set("cursor", cursor)
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("list pinned messages error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("list pinned messages", res)
+	}
+
+	resp := &RespCommon[*pinnedMessagesResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("list pinned messages error: %w", e)
+	}
+
+	page := &PinnedMessagesPage{Messages: []*PinnedMessage{}}
+	if resp.Data == nil {
+		return page, nil
+	}
+
+	page.Cursor = resp.Data.Cursor
+	for _, v := range resp.Data.List {
+		page.Messages = append(page.Messages, &PinnedMessage{
+			MsgId:    v.MsgId,
+			Operator: v.Operator,
+			PinnedAt: time.UnixMilli(v.PinnedAt),
+		})
+	}
+
+	return page, nil
+}
@@ -0,0 +1,296 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecallMessage(t *testing.T) {
+	var got []*recallMessageReq
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/messages/msg_recall", func(w http.ResponseWriter, r *http.Request) {
+		req := &recallMessageReq{}
+		decodeBody(t, r, req)
+		got = append(got, req)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"recalled": "yes", "msg_id": req.MsgId, "to": req.To},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	if e := em.RecallMessage(ctx, "m1", "bob", ChatTypeChat, "alice", false); e != nil {
+		t.Fatal(e)
+	}
+
+	if e := em.RecallMessage(ctx, "m2", "g1", ChatTypeGroupChat, "", true); e != nil {
+		t.Fatal(e)
+	}
+
+	want := []recallMessageReq{
+		{MsgId: "m1", To: "bob", ChatType: ChatTypeChat, From: "alice"},
+		{MsgId: "m2", To: "g1", ChatType: ChatTypeGroupChat, Force: true},
+	}
+	for i, v := range want {
+		if *got[i] != v {
+			t.Errorf("unexpected request %d: %+v", i, got[i])
+		}
+	}
+}
+
+func TestRecallMessageErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/messages/msg_recall", func(w http.ResponseWriter, r *http.Request) {
+		req := &recallMessageReq{}
+		decodeBody(t, r, req)
+
+		switch req.MsgId {
+		case "old":
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"data": map[string]interface{}{"recallfailed": "exceed recall time limit"},
+			})
+		case "missing":
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"data": map[string]interface{}{"recallfailed": "msg not found"},
+			})
+		default:
+			writeError(w, http.StatusNotFound, "service_resource_not_found", "message not found")
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	e := em.RecallMessage(ctx, "old", "bob", ChatTypeChat, "alice", false)
+	if !errors.Is(e, ErrRecallWindowExceeded) || errors.Is(e, ErrMessageNotFound) {
+		t.Errorf("expected window exceeded, got %v", e)
+	}
+
+	if e := em.RecallMessage(ctx, "missing", "bob", ChatTypeChat, "alice", false); !errors.Is(e, ErrMessageNotFound) {
+		t.Errorf("expected message not found, got %v", e)
+	}
+
+	e = em.RecallMessage(ctx, "gone", "g1", ChatTypeGroupChat, "", false)
+	if !errors.Is(e, ErrMessageNotFound) || !errors.Is(e, ErrNotFound) {
+		t.Errorf("expected message not found from 404, got %v", e)
+	}
+}
+
+func TestRecallMessageInvalidChatType(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	if e := em.RecallMessage(context.Background(), "m1", "bob", "single", "alice", false); e == nil {
+		t.Error("expected error for invalid chat type")
+	}
+}
+
+func TestGetMessageStatus(t *testing.T) {
+	// 离线消息状态和各接收方的已读状态
+	offline := map[string]string{"m1": "undelivered", "m2": "delivered", "m3": "delivered", "m4": "delivered"}
+	read := map[string]bool{"m2": true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/bob/offline_msg_status/{msgID}", func(w http.ResponseWriter, r *http.Request) {
+		msgID := r.PathValue("msgID")
+		if msgID == "gone" {
+			writeError(w, http.StatusNotFound, "service_resource_not_found", "message not found")
+			return
+		}
+
+		data := map[string]string{}
+		if v, ok := offline[msgID]; ok {
+			data[msgID] = v
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+	})
+	mux.HandleFunc("GET /org/app/messages/{msgID}/status", func(w http.ResponseWriter, r *http.Request) {
+		msgID := r.PathValue("msgID")
+		if msgID == "m4" {
+			writeError(w, http.StatusForbidden, "service_not_open", "read ack is not open")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"userId": "carol", "delivered": true, "read": true},
+				{"userId": "bob", "delivered": true, "read": read[msgID]},
+			},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	for msgID, want := range map[string]MessageDeliveryStatus{
+		"m1": MessageUndelivered,
+		"m2": MessageRead,
+		"m3": MessageDelivered,
+		"m4": MessageDelivered, // 未开通已读回执时只能确认已送达
+	} {
+		status, e := em.GetMessageStatus(context.Background(), "bob", msgID)
+		if e != nil {
+			t.Errorf("%s: %v", msgID, e)
+			continue
+		}
+
+		if status != want {
+			t.Errorf("%s: got %s, want %s", msgID, status, want)
+		}
+	}
+
+	// 未知消息和未送达区分开
+	for _, msgID := range []string{"unknown", "gone"} {
+		status, e := em.GetMessageStatus(context.Background(), "bob", msgID)
+		if !errors.Is(e, ErrMessageNotFound) || status != "" {
+			t.Errorf("%s: expected message not found, got %q, %v", msgID, status, e)
+		}
+	}
+}
+
+func TestPinMessage(t *testing.T) {
+	const pinLimit = 2
+
+	// 群组 g1 中的置顶消息, 按置顶顺序排列
+	var pinned []pinMessageReq
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/messages/pin", func(w http.ResponseWriter, r *http.Request) {
+		req := pinMessageReq{}
+		decodeBody(t, r, &req)
+
+		if len(pinned) >= pinLimit {
+			writeError(w, http.StatusForbidden, "pin_message_exceed_limit", "pinned message count exceed limit")
+			return
+		}
+
+		pinned = append(pinned, req)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]bool{"result": true}})
+	})
+	mux.HandleFunc("POST /org/app/messages/unpin", func(w http.ResponseWriter, r *http.Request) {
+		req := pinMessageReq{}
+		decodeBody(t, r, &req)
+
+		for i, v := range pinned {
+			if v.MsgId == req.MsgId {
+				pinned = append(pinned[:i], pinned[i+1:]...)
+				break
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]bool{"result": true}})
+	})
+	mux.HandleFunc("GET /org/app/messages/pin", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("conversationId") != "g1" || q.Get("type") != "groupchat" || q.Get("limit") != "10" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		list := []map[string]interface{}{}
+		for i, v := range pinned {
+			list = append(list, map[string]interface{}{"msgId": v.MsgId, "operator": v.Operator, "pinnedTime": 1710000000000 + i})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"list": list}})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	for _, msgID := range []string{"m1", "m2"} {
+		if e := em.PinMessage(ctx, ChatTypeGroupChat, "g1", msgID, "alice"); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	if pinned[0] != (pinMessageReq{ConversationId: "g1", Type: ChatTypeGroupChat, MsgId: "m1", Operator: "alice"}) {
+		t.Errorf("unexpected request: %+v", pinned[0])
+	}
+
+	// 超过置顶数量上限
+	e := em.PinMessage(ctx, ChatTypeGroupChat, "g1", "m3", "alice")
+	if !errors.Is(e, ErrPinLimitExceeded) || !errors.Is(e, ErrForbidden) {
+		t.Errorf("expected pin limit exceeded, got %v", e)
+	}
+
+	if e := em.UnpinMessage(ctx, ChatTypeGroupChat, "g1", "m1", "bob"); e != nil {
+		t.Fatal(e)
+	}
+
+	page, e := em.ListPinnedMessages(ctx, ChatTypeGroupChat, "g1", 10, "")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := []*PinnedMessage{{MsgId: "m2", Operator: "alice", PinnedAt: time.UnixMilli(1710000000000)}}
+	if !reflect.DeepEqual(page.Messages, want) || page.Cursor != "" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	if e := em.PinMessage(ctx, ChatTypeChat, "bob", "m1", "alice"); e == nil {
+		t.Error("expected error for single chat")
+	}
+}
+
+func TestSearchMessages(t *testing.T) {
+	var queries []url.Values
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/messages/{target}", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+
+		cursor := ""
+		if r.PathValue("target") == "users" {
+			cursor = "next"
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"msg_id": "m1", "from": "alice", "to": "bob", "chat_type": "chat", "timestamp": 1710000000000, "payload": map[string]interface{}{}},
+			},
+			"cursor": cursor,
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	result, e := em.SearchMessages(ctx, &SearchMessageReq{
+		Keyword:   "订单",
+		From:      "alice",
+		To:        "bob",
+		Start:     time.UnixMilli(1710000000000),
+		End:       time.UnixMilli(1710086400000),
+		MsgType:   "txt",
+		Direction: "desc",
+		Limit:     20,
+		Cursor:    "c1",
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if result.Cursor != "next" || len(result.Messages) != 1 || result.Messages[0].MsgId != "m1" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if _, e := em.SearchMessages(ctx, &SearchMessageReq{Keyword: "hi", Group: true}); e != nil {
+		t.Fatal(e)
+	}
+
+	want := []url.Values{
+		{
+			"keyword": {"订单"}, "from": {"alice"}, "to": {"bob"},
+			"start": {"1710000000000"}, "end": {"1710086400000"},
+			"msg_type": {"txt"}, "direction": {"desc"}, "limit": {"20"}, "cursor": {"c1"},
+		},
+		{"keyword": {"hi"}},
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("unexpected queries: %v", queries)
+	}
+}
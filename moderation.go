@@ -0,0 +1,244 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"unicode/utf8"
+	"uw/ureq"
+)
+
+// ReportReason 举报原因
+type ReportReason string
+
+const (
+	ReportReasonSpam       ReportReason = "spam"       // 垃圾广告
+	ReportReasonAbuse      ReportReason = "abuse"      // 辱骂攻击
+	ReportReasonNudity     ReportReason = "nudity"     // 色情低俗
+	ReportReasonViolence   ReportReason = "violence"   // 暴力恐怖
+	ReportReasonPolitics   ReportReason = "politics"   // 政治敏感
+	ReportReasonFraud      ReportReason = "fraud"      // 诈骗
+	ReportReasonIllegal    ReportReason = "illegal"    // 违法违规
+	ReportReasonInfringing ReportReason = "infringing" // 侵权
+	ReportReasonOther      ReportReason = "other"      // 其他
+)
+
+type reportUserReq struct {
+	Reporter string       `json:"reporter"` // 举报人
+	Reported string       `json:"reported"` // 被举报用户
+	Reason   ReportReason `json:"reason"`   // 举报原因
+}
+
+// ReportDetailMaxLen 举报说明的最大长度 (字符数)
+const ReportDetailMaxLen = 512
+
+type reportMessageReq struct {
+	Reporter   string       `json:"reporter"`         // 举报人
+	MsgId      string       `json:"msg_id"`           // 被举报消息 ID
+	Reason     ReportReason `json:"reason"`           // 举报原因
+	Detail     string       `json:"detail,omitempty"` // 举报说明
+	TargetType ChatType     `json:"target_type"`      // 消息所在会话类型
+	TargetId   string       `json:"target_id"`        // 消息所在会话 (用户, 群组或聊天室 ID)
+}
+
+// ReportUser 举报用户
+// reporter: 举报人, reported: 被举报用户, reason: 举报原因
+func (em *Easemob) ReportUser(ctx context.Context, reporter, reported string, reason ReportReason) error {
+	if len(reporter) < 1 || len(reported) < 1 || len(reason) < 1 {
+		return errors.New("report user error: invalid params")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("moderation/users/report").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&reportUserReq{
+			Reporter: reporter,
+			Reported: reported,
+			Reason:   reason,
+		}))
+	if e != nil {
+		return fmt.Errorf("report user error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("report user", res)
+	}
+
+	return nil
+}
+
+// ReportMessage 举报消息, 举报会出现在 Easemob 控制台的内容审核中
+// 重复举报同一条消息不会返回错误, 消息不存在时返回的错误满足 errors.Is(e, ErrMessageNotFound)
+// msgID: 被举报消息 ID, reporter: 举报人, reason: 举报原因, detail: 举报说明, 可为空, 最多 ReportDetailMaxLen 个字符,
+// targetType: 消息所在会话类型, targetID: 消息所在会话 (用户, 群组或聊天室 ID)
+func (em *Easemob) ReportMessage(ctx context.Context, msgID, reporter string, reason ReportReason, detail string, targetType ChatType, targetID string) error {
+	if len(reporter) < 1 || len(msgID) < 1 || len(targetID) < 1 {
+		return errors.New("report message error: msg id, reporter or target id is empty")
+	}
+
+	if len(reason) < 1 {
+		return errors.New("report message error: reason is empty")
+	}
+
+	if utf8.RuneCountInString(detail) > ReportDetailMaxLen {
+		return fmt.Errorf("report message error: detail must not exceed %d characters", ReportDetailMaxLen)
+	}
+
+	switch targetType {
+	case ChatTypeChat, ChatTypeGroupChat, ChatTypeChatroom:
+	default:
+		return fmt.Errorf("report message error: invalid target type %q", targetType)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("moderation/messages/report").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&reportMessageReq{
+			Reporter:   reporter,
+			MsgId:      msgID,
+			Reason:     reason,
+			Detail:     detail,
+			TargetType: targetType,
+			TargetId:   targetID,
+		}))
+	if e != nil {
+		return fmt.Errorf("report message error: %w", e)
+	}
+
+	if !res.OK() {
+		if res.StatusCode == http.StatusConflict {
+			return nil
+		}
+
+		e := newResponseError("report message", res)
+		if errors.Is(e, ErrNotFound) {
+			return fmt.Errorf("%w: %w", ErrMessageNotFound, e)
+		}
+
+		return e
+	}
+
+	return nil
+}
+
+// ModerationRecordMaxRange ListModerationRecords 单次查询的最大时间范围
+const ModerationRecordMaxRange = 7 * 24 * time.Hour
+
+type ModerationRecord struct {
+	MsgId      string    // 消息 ID
+	From       string    // 发送方
+	To         string    // 接收方 (用户, 群组或聊天室 ID)
+	ChatType   ChatType  // 会话类型
+	Categories []string  // 违规类型, 例如 porn, politics, ad
+	Action     string    // 处理方式, 例如 block (拦截), flag (标记), replace (替换)
+	CreatedAt  time.Time // 审核时间
+}
+
+type ModerationRecordsPage struct {
+	Records []*ModerationRecord // 审核记录
+	Cursor  string              // 下一页游标, 为空表示没有更多数据
+}
+
+type moderationRecordsResp struct {
+	List []*struct {
+		MsgId      string   `json:"msg_id"`
+		From       string   `json:"from"`
+		To         string   `json:"to"`
+		ChatType   ChatType `json:"chat_type"`
+		Categories []string `json:"categories"`
+		Action     string   `json:"action"`
+		Timestamp  int64    `json:"timestamp"` // Unix 时间戳, 单位为毫秒
+	} `json:"list"`
+	Cursor string `json:"cursor"`
+}
+
+// ListModerationRecords 分页查询内容审核拦截或标记的消息记录
+// [startTime, endTime) 不能超过 ModerationRecordMaxRange
+// startTime: 开始时间, endTime: 结束时间, limit: 每页数量, cursor: 分页游标, 首页传空
+func (em *Easemob) ListModerationRecords(ctx context.Context, startTime, endTime time.Time, limit int, cursor string) (*ModerationRecordsPage, error) {
+	if !startTime.Before(endTime) {
+		return nil, errors.New("list moderation records error: start time must be before end time")
+	}
+
+	if endTime.Sub(startTime) > ModerationRecordMaxRange {
+		return nil, fmt.Errorf("list moderation records error: time range must not exceed %s", ModerationRecordMaxRange)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL("moderation/records")
+	q := url.Values{}
+	q.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	q.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if len(cursor) > 0 {
+		q.Set("cursor", cursor)
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("list moderation records error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("list moderation records", res)
+	}
+
+	resp := &RespCommon[*moderationRecordsResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("list moderation records error: %w", e)
+	}
+
+	page := &ModerationRecordsPage{Records: []*ModerationRecord{}}
+	if resp.Data == nil {
+		return page, nil
+	}
+
+	page.Cursor = resp.Data.Cursor
+	for _, v := range resp.Data.List {
+		page.Records = append(page.Records, &ModerationRecord{
+			MsgId:      v.MsgId,
+			From:       v.From,
+			To:         v.To,
+			ChatType:   v.ChatType,
+			Categories: v.Categories,
+			Action:     v.Action,
+			CreatedAt:  time.UnixMilli(v.Timestamp),
+		})
+	}
+
+	return page, nil
+}
+
+// IterateModerationRecords 返回内容审核记录的分页迭代器
+// startTime: 开始时间, endTime: 结束时间, limit: 每页数量
+func (em *Easemob) IterateModerationRecords(startTime, endTime time.Time, limit int) *Pager[*ModerationRecord] {
+	return newPager(func(ctx context.Context, cursor string) ([]*ModerationRecord, string, error) {
+		page, e := em.ListModerationRecords(ctx, startTime, endTime, limit, cursor)
+		if e != nil {
+			return nil, "", e
+		}
+
+		return page.Records, page.Cursor, nil
+	})
+}
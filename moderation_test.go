@@ -0,0 +1,134 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestReportMessage(t *testing.T) {
+	reports := map[string]int{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/moderation/messages/report", func(w http.ResponseWriter, r *http.Request) {
+		req := &reportMessageReq{}
+		decodeBody(t, r, req)
+
+		want := reportMessageReq{Reporter: "alice", MsgId: req.MsgId, Reason: ReportReasonSpam, Detail: "发了广告链接", TargetType: ChatTypeGroupChat, TargetId: "g1"}
+		if *req != want {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		switch {
+		case req.MsgId == "missing":
+			writeError(w, http.StatusNotFound, "service_resource_not_found", "message not found")
+		case reports[req.MsgId] > 0:
+			writeError(w, http.StatusConflict, "duplicate_unique_property_exists", "already reported")
+		default:
+			reports[req.MsgId]++
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]bool{"result": true}})
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	// 重复举报不会返回错误
+	for i := 0; i < 2; i++ {
+		if e := em.ReportMessage(ctx, "m1", "alice", ReportReasonSpam, "发了广告链接", ChatTypeGroupChat, "g1"); e != nil {
+			t.Fatalf("report %d: %v", i, e)
+		}
+	}
+
+	e := em.ReportMessage(ctx, "missing", "alice", ReportReasonSpam, "发了广告链接", ChatTypeGroupChat, "g1")
+	if !errors.Is(e, ErrMessageNotFound) || !errors.Is(e, ErrNotFound) {
+		t.Errorf("expected message not found, got %v", e)
+	}
+}
+
+func TestReportMessageValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	ctx := context.Background()
+
+	if e := em.ReportMessage(ctx, "m1", "alice", "", "", ChatTypeChat, "bob"); e == nil {
+		t.Error("expected error for empty reason")
+	}
+
+	long := make([]rune, ReportDetailMaxLen+1)
+	for i := range long {
+		long[i] = '长'
+	}
+	if e := em.ReportMessage(ctx, "m1", "alice", ReportReasonOther, string(long), ChatTypeChat, "bob"); e == nil {
+		t.Error("expected error for detail too long")
+	}
+
+	if e := em.ReportMessage(ctx, "m1", "alice", ReportReasonSpam, "", "channel", "bob"); e == nil {
+		t.Error("expected error for invalid target type")
+	}
+}
+
+func TestIterateModerationRecords(t *testing.T) {
+	start := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/moderation/records", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("startTime") != strconv.FormatInt(start.UnixMilli(), 10) || q.Get("endTime") != strconv.FormatInt(end.UnixMilli(), 10) || q.Get("limit") != "2" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch q.Get("cursor") {
+		case "":
+			_, _ = w.Write([]byte(`{"data": {
+				"list": [
+					{"msg_id": "m1", "from": "alice", "to": "bob", "chat_type": "chat", "categories": ["porn"], "action": "block", "timestamp": 1709942400000},
+					{"msg_id": "m2", "from": "carol", "to": "g1", "chat_type": "groupchat", "categories": ["ad", "politics"], "action": "flag", "timestamp": 1709946000000}
+				],
+				"cursor": "page-2"
+			}}`))
+		case "page-2":
+			_, _ = w.Write([]byte(`{"data": {
+				"list": [
+					{"msg_id": "m3", "from": "dave", "to": "r1", "chat_type": "chatroom", "categories": ["abuse"], "action": "replace", "timestamp": 1709949600000}
+				]
+			}}`))
+		default:
+			t.Errorf("unexpected cursor: %q", q.Get("cursor"))
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+
+	records, e := em.IterateModerationRecords(start, end, 2).All(context.Background())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := []*ModerationRecord{
+		{MsgId: "m1", From: "alice", To: "bob", ChatType: ChatTypeChat, Categories: []string{"porn"}, Action: "block", CreatedAt: time.UnixMilli(1709942400000)},
+		{MsgId: "m2", From: "carol", To: "g1", ChatType: ChatTypeGroupChat, Categories: []string{"ad", "politics"}, Action: "flag", CreatedAt: time.UnixMilli(1709946000000)},
+		{MsgId: "m3", From: "dave", To: "r1", ChatType: ChatTypeChatroom, Categories: []string{"abuse"}, Action: "replace", CreatedAt: time.UnixMilli(1709949600000)},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("unexpected records: %+v", records)
+	}
+}
+
+func TestListModerationRecordsRange(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	now := time.Now()
+
+	if _, e := em.ListModerationRecords(context.Background(), now, now, 10, ""); e == nil {
+		t.Error("expected error for empty range")
+	}
+
+	if _, e := em.ListModerationRecords(context.Background(), now.Add(-ModerationRecordMaxRange-time.Second), now, 10, ""); e == nil {
+		t.Error("expected error for range too long")
+	}
+}
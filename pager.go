@@ -0,0 +1,69 @@
+package easemob
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pager 基于游标的分页迭代器
+// 使用方法:
+//
+//	for p.HasNext() {
+//		items, e := p.Next(ctx)
+//		...
+//	}
+type Pager[T any] struct {
+	fetch  func(ctx context.Context, cursor string) ([]T, string, error)
+	cursor string
+	done   bool
+}
+
+func newPager[T any](fetch func(ctx context.Context, cursor string) ([]T, string, error)) *Pager[T] {
+	return &Pager[T]{fetch: fetch}
+}
+
+// HasNext 是否还有下一页
+func (p *Pager[T]) HasNext() bool {
+	return !p.done
+}
+
+// Next 获取下一页数据, 出错时可以重试
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	items, cursor, e := p.fetch(ctx, p.cursor)
+	if e != nil {
+		return nil, e
+	}
+
+	// 服务端重复返回同一个游标时停止, 避免无限循环
+	if len(cursor) > 0 && cursor == p.cursor {
+		p.done = true
+		return items, fmt.Errorf("pager error: cursor %q is repeated", cursor)
+	}
+
+	p.cursor = cursor
+	p.done = len(cursor) < 1
+	return items, nil
+}
+
+// Cursor 当前游标, 可用于断点续传
+func (p *Pager[T]) Cursor() string {
+	return p.cursor
+}
+
+// All 遍历所有分页并返回全部数据
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	all := []T{}
+	for p.HasNext() {
+		items, e := p.Next(ctx)
+		all = append(all, items...)
+		if e != nil {
+			return all, e
+		}
+	}
+
+	return all, nil
+}
@@ -0,0 +1,119 @@
+package easemob
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RawBody 未知类型的消息体, 保留原始内容
+type RawBody struct {
+	Type string          // 消息类型
+	Data json.RawMessage // 原始消息体
+}
+
+func (b RawBody) MessageType() string { return b.Type }
+
+func (b RawBody) MarshalJSON() ([]byte, error) {
+	return b.Data, nil
+}
+
+type ReceivedMessage struct {
+	MsgId     string      // 消息 ID
+	From      string      // 发送方
+	To        string      // 接收方 (用户, 群组或聊天室 ID)
+	ChatType  ChatType    // 会话类型
+	Timestamp time.Time   // 发送时间
+	Body      MessageBody // 消息体, 例如 *TextBody, *ImageBody, 未知类型为 *RawBody
+	Ext       Ext         // 消息扩展字段
+}
+
+type receivedMessageJSON struct {
+	MsgId     string   `json:"msg_id"`
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	ChatType  ChatType `json:"chat_type"`
+	Timestamp int64    `json:"timestamp"` // Unix 时间戳, 单位为毫秒
+	Payload   struct {
+		Bodies []json.RawMessage `json:"bodies"`
+		Ext    Ext               `json:"ext"`
+	} `json:"payload"`
+}
+
+// newReceivedBody 各消息类型对应的消息体
+var newReceivedBody = map[string]func() MessageBody{
+	"txt":    func() MessageBody { return &TextBody{} },
+	"img":    func() MessageBody { return &ImageBody{} },
+	"audio":  func() MessageBody { return &AudioBody{} },
+	"video":  func() MessageBody { return &VideoBody{} },
+	"file":   func() MessageBody { return &FileBody{} },
+	"loc":    func() MessageBody { return &LocationBody{} },
+	"cmd":    func() MessageBody { return &CmdBody{} },
+	"custom": func() MessageBody { return &CustomBody{} },
+}
+
+// ParseMessagePayload 解析回调和历史消息文件中的消息 JSON
+// 消息体按 type 解析为对应的 MessageBody (指针), 未知类型解析为 *RawBody 而不返回错误
+// raw: 消息 JSON
+func ParseMessagePayload(raw []byte) (*ReceivedMessage, error) {
+	v := &receivedMessageJSON{}
+	if e := json.Unmarshal(raw, v); e != nil {
+		return nil, fmt.Errorf("parse message payload error: %w", e)
+	}
+
+	if len(v.Payload.Bodies) < 1 {
+		return nil, errors.New("parse message payload error: bodies is empty")
+	}
+
+	body, e := parseMessageBody(v.Payload.Bodies[0])
+	if e != nil {
+		return nil, fmt.Errorf("parse message payload error: %w", e)
+	}
+
+	return &ReceivedMessage{
+		MsgId:     v.MsgId,
+		From:      v.From,
+		To:        v.To,
+		ChatType:  v.ChatType,
+		Timestamp: time.UnixMilli(v.Timestamp),
+		Body:      body,
+		Ext:       v.Payload.Ext,
+	}, nil
+}
+
+func parseMessageBody(data json.RawMessage) (MessageBody, error) {
+	head := &struct {
+		Type string `json:"type"`
+	}{}
+	if e := json.Unmarshal(data, head); e != nil {
+		return nil, e
+	}
+
+	newBody, ok := newReceivedBody[head.Type]
+	if !ok {
+		return &RawBody{Type: head.Type, Data: data}, nil
+	}
+
+	body := newBody()
+	if e := json.Unmarshal(data, body); e != nil {
+		return nil, fmt.Errorf("%s body: %w", head.Type, e)
+	}
+
+	return body, nil
+}
+
+// ExtString 获取字符串类型的扩展字段, 参见 Ext.GetString
+func (m *ReceivedMessage) ExtString(key string) (string, bool) {
+	return m.Ext.GetString(key)
+}
+
+// ExtInt 获取整数类型的扩展字段, 参见 Ext.GetInt
+func (m *ReceivedMessage) ExtInt(key string) (int64, bool) {
+	return m.Ext.GetInt(key)
+}
+
+// ExtBool 获取布尔类型的扩展字段, 参见 Ext.GetBool
+func (m *ReceivedMessage) ExtBool(key string) (bool, bool) {
+	return m.Ext.GetBool(key)
+}
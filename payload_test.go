@@ -0,0 +1,125 @@
+package easemob
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// payloadFixture 包含指定消息体的回调消息
+func payloadFixture(body string) []byte {
+	return []byte(`{
+		"msg_id": "m1",
+		"from": "alice",
+		"to": "g1",
+		"chat_type": "groupchat",
+		"timestamp": 1710000000123,
+		"payload": {"bodies": [` + body + `], "ext": {"orderId": "o-1", "count": 3, "urgent": true}}
+	}`)
+}
+
+func TestParseMessagePayload(t *testing.T) {
+	for _, v := range []struct {
+		body string
+		want MessageBody
+	}{
+		{`{"type": "txt", "msg": "你好, 世界 👋"}`, &TextBody{Msg: "你好, 世界 👋"}},
+		{
+			`{"type": "img", "filename": "a.jpg", "url": "https://example.com/a.jpg", "secret": "s", "size": {"width": 640, "height": 480}, "file_length": 1024}`,
+			&ImageBody{Filename: "a.jpg", Url: "https://example.com/a.jpg", Secret: "s", Size: &ImageSize{Width: 640, Height: 480}, FileLength: 1024},
+		},
+		{
+			`{"type": "audio", "filename": "a.amr", "url": "https://example.com/a.amr", "length": 12}`,
+			&AudioBody{Filename: "a.amr", Url: "https://example.com/a.amr", Length: 12},
+		},
+		{
+			`{"type": "video", "filename": "v.mp4", "url": "https://example.com/v.mp4", "length": 30, "thumb": "https://example.com/v.jpg"}`,
+			&VideoBody{Filename: "v.mp4", Url: "https://example.com/v.mp4", Length: 30, Thumb: "https://example.com/v.jpg"},
+		},
+		{
+			`{"type": "file", "filename": "报告.pdf", "url": "https://example.com/f.pdf", "file_length": 2048}`,
+			&FileBody{Filename: "报告.pdf", Url: "https://example.com/f.pdf", FileLength: 2048},
+		},
+		{
+			`{"type": "loc", "lat": 39.9, "lng": 116.4, "addr": "北京"}`,
+			&LocationBody{Lat: 39.9, Lng: 116.4, Addr: "北京"},
+		},
+		{`{"type": "cmd", "action": "refresh"}`, &CmdBody{Action: "refresh"}},
+		{
+			`{"type": "custom", "customEvent": "gift", "customExts": {"id": "rose"}}`,
+			&CustomBody{CustomEvent: "gift", CustomExts: map[string]string{"id": "rose"}},
+		},
+	} {
+		msg, e := ParseMessagePayload(payloadFixture(v.body))
+		if e != nil {
+			t.Errorf("%s: %v", v.want.MessageType(), e)
+			continue
+		}
+
+		if !reflect.DeepEqual(msg.Body, v.want) {
+			t.Errorf("%s: unexpected body %+v", v.want.MessageType(), msg.Body)
+		}
+
+		if msg.MsgId != "m1" || msg.From != "alice" || msg.To != "g1" || msg.ChatType != ChatTypeGroupChat ||
+			!msg.Timestamp.Equal(time.UnixMilli(1710000000123)) {
+			t.Errorf("%s: unexpected message %+v", v.want.MessageType(), msg)
+		}
+	}
+}
+
+func TestParseMessagePayloadUnknownType(t *testing.T) {
+	body := `{"type": "combine", "title": "聊天记录", "summary": "..."}`
+
+	msg, e := ParseMessagePayload(payloadFixture(body))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	raw, ok := msg.Body.(*RawBody)
+	if !ok || raw.MessageType() != "combine" {
+		t.Fatalf("unexpected body: %#v", msg.Body)
+	}
+
+	// 未知类型的消息体序列化时保持原样
+	data, e := json.Marshal(raw)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assertJSON(t, data, body)
+}
+
+func TestParseMessagePayloadExt(t *testing.T) {
+	msg, e := ParseMessagePayload(payloadFixture(`{"type": "txt", "msg": "hi"}`))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if v, ok := msg.ExtString("orderId"); !ok || v != "o-1" {
+		t.Errorf("unexpected orderId: %q, %v", v, ok)
+	}
+
+	if v, ok := msg.ExtInt("count"); !ok || v != 3 {
+		t.Errorf("unexpected count: %d, %v", v, ok)
+	}
+
+	if v, ok := msg.ExtBool("urgent"); !ok || !v {
+		t.Errorf("unexpected urgent: %v, %v", v, ok)
+	}
+
+	if _, ok := msg.ExtInt("orderId"); ok {
+		t.Error("string ext should not be read as int")
+	}
+}
+
+func TestParseMessagePayloadInvalid(t *testing.T) {
+	for _, raw := range []string{
+		`not json`,
+		`{"msg_id": "m1", "payload": {"bodies": []}}`,
+		`{"msg_id": "m1", "payload": {"bodies": [{"type": "txt", "msg": 1}]}}`,
+	} {
+		if _, e := ParseMessagePayload([]byte(raw)); e == nil {
+			t.Errorf("expected error for %s", raw)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+	"uw/ureq"
+)
+
+// 用户在线状态, 其他值由业务自定义
+const (
+	PresenceOffline = 0 // 离线
+	PresenceOnline  = 1 // 在线
+	PresenceBusy    = 2 // 忙碌
+	PresenceAway    = 3 // 离开
+)
+
+type PresenceInfo struct {
+	UserId   string            // 用户 ID
+	Resource string            // 设备资源
+	Status   int               // 在线状态
+	Ext      map[string]string // 自定义扩展信息
+	Expiry   time.Time         // 状态过期时间
+}
+
+type presenceInfoResp struct {
+	UserId   string            `json:"uid"`
+	Resource string            `json:"resource"`
+	Status   int               `json:"status"`
+	Ext      map[string]string `json:"ext"`
+	Expiry   int64             `json:"expiry"` // Unix 时间戳, 单位为秒
+}
+
+func (v *presenceInfoResp) info() *PresenceInfo {
+	info := &PresenceInfo{
+		UserId:   v.UserId,
+		Resource: v.Resource,
+		Status:   v.Status,
+		Ext:      v.Ext,
+	}
+
+	if v.Expiry > 0 {
+		info.Expiry = time.Unix(v.Expiry, 0)
+	}
+
+	return info
+}
+
+type setPresenceReq struct {
+	Ext map[string]string `json:"ext,omitempty"`
+}
+
+// SetPresence 设置用户在指定设备上的在线状态
+// userId: 用户 ID, resource: 设备资源, ext: 自定义扩展信息, status: 在线状态, 见 PresenceOnline 等
+func (em *Easemob) SetPresence(ctx context.Context, userId, resource string, ext map[string]string, status int) error {
+	if len(userId) < 1 || len(resource) < 1 {
+		return errors.New("set presence error: user id or resource is empty")
+	}
+
+	if status < 0 {
+		return errors.New("set presence error: invalid status")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("presence/v1", userId, resource, strconv.Itoa(status))).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&setPresenceReq{
+			Ext: ext,
+		}))
+	if e != nil {
+		return fmt.Errorf("set presence error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("set presence", res)
+	}
+
+	return nil
+}
+
+// GetPresence 获取用户在各设备上的在线状态
+// userId: 用户 ID
+func (em *Easemob) GetPresence(ctx context.Context, userId string) ([]*PresenceInfo, error) {
+	if len(userId) < 1 {
+		return nil, errors.New("get presence error: user id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("presence/v1", userId)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get presence error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get presence", res)
+	}
+
+	resp := &RespCommon[[]*presenceInfoResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get presence error: %w", e)
+	}
+
+	infos := make([]*PresenceInfo, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		infos = append(infos, v.info())
+	}
+
+	return infos, nil
+}
+
+type presenceSubReq struct {
+	Usernames []string `json:"usernames"`
+}
+
+type presenceSubResp struct {
+	Result []*presenceInfoResp `json:"result"`
+}
+
+// SubscribePresence 订阅其他用户的在线状态, 返回被订阅用户的当前状态
+// subscriber: 订阅者, targets: 被订阅的用户, expirySeconds: 订阅时长, 单位为秒
+func (em *Easemob) SubscribePresence(ctx context.Context, subscriber string, targets []string, expirySeconds int) ([]*PresenceInfo, error) {
+	if len(subscriber) < 1 || len(targets) < 1 {
+		return nil, errors.New("subscribe presence error: subscriber or targets is empty")
+	}
+
+	if expirySeconds < 1 {
+		return nil, errors.New("subscribe presence error: invalid expiry")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("presence/v1", subscriber, "sub", strconv.Itoa(expirySeconds))).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&presenceSubReq{
+			Usernames: targets,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("subscribe presence error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("subscribe presence", res)
+	}
+
+	resp := &RespCommon[*presenceSubResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("subscribe presence error: %w", e)
+	}
+
+	infos := []*PresenceInfo{}
+	if resp.Data != nil {
+		for _, v := range resp.Data.Result {
+			infos = append(infos, v.info())
+		}
+	}
+
+	return infos, nil
+}
+
+// UnsubscribePresence 取消订阅其他用户的在线状态
+// subscriber: 订阅者, targets: 取消订阅的用户
+func (em *Easemob) UnsubscribePresence(ctx context.Context, subscriber string, targets []string) error {
+	if len(subscriber) < 1 || len(targets) < 1 {
+		return errors.New("unsubscribe presence error: subscriber or targets is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("presence/v1", subscriber, "sub")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&presenceSubReq{
+			Usernames: targets,
+		}))
+	if e != nil {
+		return fmt.Errorf("unsubscribe presence error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("unsubscribe presence", res)
+	}
+
+	return nil
+}
+
+// GetSubscribedPresences 分页获取订阅者已订阅的用户及其在线状态
+// subscriber: 订阅者, pageNum: 页码 (从 1 开始), pageSize: 每页数量
+func (em *Easemob) GetSubscribedPresences(ctx context.Context, subscriber string, pageNum, pageSize int) ([]*PresenceInfo, error) {
+	if len(subscriber) < 1 {
+		return nil, errors.New("get subscribed presences error: subscriber is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL(path.Join("presence/v1", subscriber, "sub"))
+	q := url.Values{}
+	if pageNum > 0 {
+		q.Set("pageNum", strconv.Itoa(pageNum))
+	}
+	if pageSize > 0 {
+		q.Set("pageSize", strconv.Itoa(pageSize))
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get subscribed presences error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get subscribed presences", res)
+	}
+
+	resp := &RespCommon[*presenceSubResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get subscribed presences error: %w", e)
+	}
+
+	infos := []*PresenceInfo{}
+	if resp.Data != nil {
+		for _, v := range resp.Data.Result {
+			infos = append(infos, v.info())
+		}
+	}
+
+	return infos, nil
+}
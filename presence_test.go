@@ -0,0 +1,145 @@
+package easemob
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSetAndGetPresence(t *testing.T) {
+	var paths []string
+	var bodies [][]byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/presence/v1/{user}/{resource}/{status}", func(w http.ResponseWriter, r *http.Request) {
+		body, e := io.ReadAll(r.Body)
+		if e != nil {
+			t.Error(e)
+		}
+
+		paths = append(paths, r.PathValue("user")+"/"+r.PathValue("resource")+"/"+r.PathValue("status"))
+		bodies = append(bodies, body)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"result": "ok"}})
+	})
+	mux.HandleFunc("GET /org/app/presence/v1/alice", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [
+			{"uid": "alice", "resource": "ios_1", "status": 2, "ext": {"text": "开会中"}, "expiry": 1710003600},
+			{"uid": "alice", "resource": "web_1", "status": 1}
+		]}`))
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	if e := em.SetPresence(ctx, "alice", "ios_1", map[string]string{"text": "开会中"}, PresenceBusy); e != nil {
+		t.Fatal(e)
+	}
+
+	if e := em.SetPresence(ctx, "alice", "web_1", nil, PresenceOnline); e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(paths, []string{"alice/ios_1/2", "alice/web_1/1"}) {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+
+	assertJSON(t, bodies[0], `{"ext": {"text": "开会中"}}`)
+	assertJSON(t, bodies[1], `{}`)
+
+	infos, e := em.GetPresence(ctx, "alice")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := []*PresenceInfo{
+		{UserId: "alice", Resource: "ios_1", Status: PresenceBusy, Ext: map[string]string{"text": "开会中"}, Expiry: time.Unix(1710003600, 0)},
+		{UserId: "alice", Resource: "web_1", Status: PresenceOnline},
+	}
+	if !reflect.DeepEqual(infos, want) {
+		t.Errorf("unexpected presence: %+v", infos)
+	}
+
+	if e := em.SetPresence(ctx, "alice", "", nil, PresenceOnline); e == nil {
+		t.Error("expected error for empty resource")
+	}
+
+	if e := em.SetPresence(ctx, "alice", "ios_1", nil, -1); e == nil {
+		t.Error("expected error for negative status")
+	}
+}
+
+func TestPresenceSubscription(t *testing.T) {
+	var subBody, unsubBody []byte
+	var expiry, query string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/presence/v1/alice/sub/{expiry}", func(w http.ResponseWriter, r *http.Request) {
+		subBody, _ = io.ReadAll(r.Body)
+		expiry = r.PathValue("expiry")
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"result": [{"uid": "bob", "resource": "android_1", "status": 1}]}}`))
+	})
+	mux.HandleFunc("DELETE /org/app/presence/v1/alice/sub", func(w http.ResponseWriter, r *http.Request) {
+		unsubBody, _ = io.ReadAll(r.Body)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"result": "ok"}})
+	})
+	mux.HandleFunc("GET /org/app/presence/v1/alice/sub", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"result": [{"uid": "bob", "status": 3}, {"uid": "carol", "status": 0}]}}`))
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	infos, e := em.SubscribePresence(ctx, "alice", []string{"bob"}, 3600)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if expiry != "3600" {
+		t.Errorf("unexpected expiry: %s", expiry)
+	}
+
+	assertJSON(t, subBody, `{"usernames": ["bob"]}`)
+	if len(infos) != 1 || infos[0].UserId != "bob" || infos[0].Resource != "android_1" || infos[0].Status != PresenceOnline {
+		t.Errorf("unexpected subscribe result: %+v", infos)
+	}
+
+	if e := em.UnsubscribePresence(ctx, "alice", []string{"bob", "carol"}); e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, unsubBody, `{"usernames": ["bob", "carol"]}`)
+
+	infos, e = em.GetSubscribedPresences(ctx, "alice", 2, 50)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if query != "pageNum=2&pageSize=50" {
+		t.Errorf("unexpected query: %s", query)
+	}
+
+	if len(infos) != 2 || infos[0].Status != PresenceAway || infos[1].UserId != "carol" {
+		t.Errorf("unexpected subscribed presences: %+v", infos)
+	}
+
+	if _, e := em.GetSubscribedPresences(ctx, "alice", 0, 0); e != nil {
+		t.Fatal(e)
+	}
+
+	if query != "" {
+		t.Errorf("unexpected query without paging: %s", query)
+	}
+
+	if _, e := em.SubscribePresence(ctx, "alice", []string{"bob"}, 0); e == nil {
+		t.Error("expected error for invalid expiry")
+	}
+}
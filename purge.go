@@ -0,0 +1,126 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// 注销用户的各个步骤
+const (
+	PurgeStepContacts     = "contacts"      // 删除好友关系
+	PurgeStepPushBindings = "push_bindings" // 解绑推送设备
+	PurgeStepMetadata     = "metadata"      // 删除用户属性
+	PurgeStepDeactivate   = "deactivate"    // 封禁用户
+	PurgeStepDisconnect   = "disconnect"    // 强制下线
+	PurgeStepDeleteUser   = "delete_user"   // 删除用户
+)
+
+type PurgeOptions struct {
+	RemoveContacts  bool // 是否删除用户的所有好友关系
+	ContactPageSize int  // 遍历好友列表时的每页数量, 默认 100
+}
+
+type PurgeReport struct {
+	Done    []string         // 已完成的步骤
+	Skipped []string         // 因资源不存在 (404) 而跳过的步骤
+	Failed  map[string]error // 失败的步骤及原因
+}
+
+// PurgeUser 注销用户 (例如 GDPR 删除): 依次删除好友关系 (可选), 解绑推送设备, 删除用户属性,
+// 封禁并强制下线, 最后删除用户
+// 资源不存在 (404) 的步骤会被跳过并继续执行; 其他错误会中止后续步骤,
+// 以免在清理完成前删除用户, 修复后可以安全地重新执行
+// username: 用户 ID, opts: 注销选项
+func (em *Easemob) PurgeUser(ctx context.Context, username string, opts PurgeOptions) (*PurgeReport, error) {
+	if len(username) < 1 {
+		return nil, errors.New("purge user error: username is empty")
+	}
+
+	report := &PurgeReport{
+		Done:    []string{},
+		Skipped: []string{},
+		Failed:  map[string]error{},
+	}
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{PurgeStepContacts, func() error { return em.purgeContacts(ctx, username, opts.ContactPageSize) }},
+		{PurgeStepPushBindings, func() error {
+			bindings, e := em.GetUserPushBindings(ctx, username)
+			if e != nil {
+				return e
+			}
+
+			for _, v := range bindings {
+				if e := em.UnbindUserPushDevice(ctx, username, v.DeviceId, v.NotifierName); e != nil &&
+					!errors.Is(e, ErrNotFound) {
+					return e
+				}
+			}
+
+			return nil
+		}},
+		{PurgeStepMetadata, func() error { return em.DeleteUserMetadata(ctx, username) }},
+		{PurgeStepDeactivate, func() error { return em.DeactivateUser(ctx, username) }},
+		{PurgeStepDisconnect, func() error { return em.DisconnectUser(ctx, username) }},
+		{PurgeStepDeleteUser, func() error {
+			_, e := em.DeleteUser(ctx, username)
+			return e
+		}},
+	}
+
+	for _, step := range steps {
+		if step.name == PurgeStepContacts && !opts.RemoveContacts {
+			continue
+		}
+
+		e := step.fn()
+		switch {
+		case e == nil:
+			report.Done = append(report.Done, step.name)
+		case errors.Is(e, ErrNotFound):
+			report.Skipped = append(report.Skipped, step.name)
+		default:
+			report.Failed[step.name] = e
+			return report, fmt.Errorf("purge user error: %s: %w", step.name, e)
+		}
+	}
+
+	return report, nil
+}
+
+// purgeContactsMaxPages 注销用户时遍历好友列表的最大页数, 避免服务端游标异常时无限遍历
+const purgeContactsMaxPages = 1000
+
+func (em *Easemob) purgeContacts(ctx context.Context, username string, pageSize int) error {
+	if pageSize < 1 {
+		pageSize = 100
+	}
+
+	// 先遍历完所有分页再删除, 避免删除过程中分页发生变化
+	contacts := []*Contact{}
+	p := em.IterateContacts(username, pageSize)
+	for pages := 0; p.HasNext(); pages++ {
+		if pages >= purgeContactsMaxPages {
+			return fmt.Errorf("purge contacts error: contacts exceed %d pages", purgeContactsMaxPages)
+		}
+
+		items, e := p.Next(ctx)
+		if e != nil {
+			return e
+		}
+
+		contacts = append(contacts, items...)
+	}
+
+	for _, v := range contacts {
+		if e := em.DeleteContact(ctx, username, v.Username); e != nil && !errors.Is(e, ErrNotFound) {
+			return e
+		}
+	}
+
+	return nil
+}
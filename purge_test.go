@@ -0,0 +1,269 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// fakePurgeServer 模拟一个用户的好友, 推送绑定, 用户属性和用户实体
+type fakePurgeServer struct {
+	mu              sync.Mutex
+	contacts        map[string]bool
+	bindings        map[string]string // device_id -> notifier_name
+	metadata        bool
+	exists          bool
+	deactivateFails bool
+}
+
+func (s *fakePurgeServer) handler(t *testing.T) http.Handler {
+	notFound := func(w http.ResponseWriter) {
+		writeError(w, http.StatusNotFound, "service_resource_not_found", "not found")
+	}
+	ok := func(w http.ResponseWriter) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/user/alice/contacts", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.exists {
+			notFound(w)
+			return
+		}
+
+		contacts := []*Contact{}
+		for v := range s.contacts {
+			contacts = append(contacts, &Contact{Username: v})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"contacts": contacts}})
+	})
+	mux.HandleFunc("DELETE /org/app/users/alice/contacts/users/{friend}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.contacts, r.PathValue("friend"))
+		ok(w)
+	})
+	mux.HandleFunc("GET /org/app/users/alice/push/binding", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.exists {
+			notFound(w)
+			return
+		}
+
+		bindings := []*PushBinding{}
+		for id, notifier := range s.bindings {
+			bindings = append(bindings, &PushBinding{DeviceId: id, NotifierName: notifier, DeviceToken: "token"})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": bindings})
+	})
+	mux.HandleFunc("PUT /org/app/users/alice/push/binding", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		req := &PushBinding{}
+		decodeBody(t, r, req)
+		if req.DeviceToken != "" {
+			t.Errorf("unbind should send an empty device token: %+v", req)
+		}
+
+		delete(s.bindings, req.DeviceId)
+		ok(w)
+	})
+	mux.HandleFunc("DELETE /org/app/metadata/user/alice", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.metadata {
+			notFound(w)
+			return
+		}
+
+		s.metadata = false
+		ok(w)
+	})
+	mux.HandleFunc("POST /org/app/users/alice/deactivate", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch {
+		case s.deactivateFails:
+			writeError(w, http.StatusInternalServerError, "internal_error", "try again later")
+		case !s.exists:
+			notFound(w)
+		default:
+			ok(w)
+		}
+	})
+	mux.HandleFunc("GET /org/app/users/alice/disconnect", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.exists {
+			notFound(w)
+			return
+		}
+		ok(w)
+	})
+	mux.HandleFunc("DELETE /org/app/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.exists {
+			notFound(w)
+			return
+		}
+
+		s.exists = false
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"entities": []map[string]interface{}{{"username": "alice", "type": "user"}},
+		})
+	})
+
+	return mux
+}
+
+func TestPurgeUserResumable(t *testing.T) {
+	s := &fakePurgeServer{
+		contacts:        map[string]bool{"bob": true, "carol": true},
+		bindings:        map[string]string{"device-1": "apns-prod"},
+		metadata:        true,
+		exists:          true,
+		deactivateFails: true,
+	}
+
+	em := newTestEasemob(t, s.handler(t))
+	opts := PurgeOptions{RemoveContacts: true}
+
+	// 第一次执行在封禁用户时失败, 用户不会被删除
+	report, e := em.PurgeUser(context.Background(), "alice", opts)
+	if e == nil {
+		t.Fatal("expected error from failed deactivate")
+	}
+
+	if report == nil || errors.Is(e, ErrNotFound) {
+		t.Fatalf("unexpected result: %+v, %v", report, e)
+	}
+
+	wantDone := []string{PurgeStepContacts, PurgeStepPushBindings, PurgeStepMetadata}
+	if !reflect.DeepEqual(report.Done, wantDone) || len(report.Skipped) != 0 {
+		t.Errorf("first run: unexpected report %+v", report)
+	}
+
+	if _, ok := report.Failed[PurgeStepDeactivate]; !ok || len(report.Failed) != 1 {
+		t.Errorf("first run: unexpected failures %v", report.Failed)
+	}
+
+	if !s.exists || len(s.contacts) != 0 || len(s.bindings) != 0 || s.metadata {
+		t.Errorf("first run: unexpected server state %+v", s)
+	}
+
+	// 修复后重新执行, 已删除的用户属性被跳过
+	s.deactivateFails = false
+
+	report, e = em.PurgeUser(context.Background(), "alice", opts)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	wantDone = []string{PurgeStepContacts, PurgeStepPushBindings, PurgeStepDeactivate, PurgeStepDisconnect, PurgeStepDeleteUser}
+	if !reflect.DeepEqual(report.Done, wantDone) ||
+		!reflect.DeepEqual(report.Skipped, []string{PurgeStepMetadata}) || len(report.Failed) != 0 {
+		t.Errorf("second run: unexpected report %+v", report)
+	}
+
+	if s.exists {
+		t.Error("second run: user should be deleted")
+	}
+
+	// 用户已删除后再次执行, 所有步骤都被跳过
+	report, e = em.PurgeUser(context.Background(), "alice", opts)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if len(report.Done) != 0 || len(report.Skipped) != 6 {
+		t.Errorf("third run: unexpected report %+v", report)
+	}
+}
+
+func TestPurgeUserContactsPaged(t *testing.T) {
+	var deleted []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/user/alice/contacts", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("limit") != "2" {
+			t.Errorf("unexpected limit: %q", q.Get("limit"))
+		}
+
+		page := map[string]map[string]interface{}{
+			"":   {"contacts": []map[string]string{{"username": "bob"}, {"username": "carol"}}, "cursor": "c1"},
+			"c1": {"contacts": []map[string]string{{"username": "dave"}}},
+		}[q.Get("cursor")]
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": page})
+	})
+	mux.HandleFunc("DELETE /org/app/users/alice/contacts/users/{friend}", func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, r.PathValue("friend"))
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "service_resource_not_found", "not found")
+	})
+
+	em := newTestEasemob(t, mux)
+
+	report, e := em.PurgeUser(context.Background(), "alice", PurgeOptions{RemoveContacts: true, ContactPageSize: 2})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(deleted, []string{"bob", "carol", "dave"}) {
+		t.Errorf("unexpected deleted contacts: %v", deleted)
+	}
+
+	if !reflect.DeepEqual(report.Done, []string{PurgeStepContacts}) || len(report.Skipped) != 5 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestPurgeUserContactsUnbounded(t *testing.T) {
+	hits := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/user/alice/contacts", func(w http.ResponseWriter, r *http.Request) {
+		// 服务端每次返回新的游标, 分页永远不会结束
+		hits++
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"contacts": []map[string]string{{"username": "bob"}},
+				"cursor":   "c" + strconv.Itoa(hits),
+			},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	report, e := em.PurgeUser(context.Background(), "alice", PurgeOptions{RemoveContacts: true})
+	if e == nil {
+		t.Fatal("expected error for unbounded contacts")
+	}
+
+	if _, ok := report.Failed[PurgeStepContacts]; !ok || len(report.Done) != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if hits != purgeContactsMaxPages {
+		t.Errorf("unexpected page requests: %d", hits)
+	}
+}
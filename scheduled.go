@@ -0,0 +1,179 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+	"uw/ureq"
+)
+
+// ScheduledMessageMinDelay 定时消息的发送时间至少晚于当前时间的时长
+const ScheduledMessageMinDelay = time.Minute
+
+type ScheduledMessageResp struct {
+	JobId string `json:"jobId"` // 定时任务 ID, 用于取消
+}
+
+type scheduledMessageReq struct {
+	SendTime int64 `json:"send_time"` // 发送时间, Unix 时间戳, 单位为毫秒
+	sendMessageReq
+}
+
+// SendScheduledMessage 发送定时单聊文本消息 (需要 Easemob 套餐支持)
+// sendAt 必须至少晚于当前时间 ScheduledMessageMinDelay
+// sendAt: 发送时间, from: 发送方, 为空时为 admin, targets: 接收方, msg: 消息内容, opts: 消息选项, 可为 nil
+func (em *Easemob) SendScheduledMessage(ctx context.Context, sendAt time.Time, from string, targets []string, msg *TextBody, opts *MessageOptions) (*ScheduledMessageResp, error) {
+	if sendAt.Before(time.Now().Add(ScheduledMessageMinDelay)) {
+		return nil, fmt.Errorf("send scheduled message error: send time must be at least %s in the future", ScheduledMessageMinDelay)
+	}
+
+	if len(targets) < 1 || len(targets) > MessageUsersMaxTargets {
+		return nil, fmt.Errorf("send scheduled message error: targets length must be in [1, %d]", MessageUsersMaxTargets)
+	}
+
+	if msg == nil || len(msg.Msg) < 1 {
+		return nil, errors.New("send scheduled message error: msg is empty")
+	}
+
+	if opts == nil {
+		opts = &MessageOptions{}
+	}
+
+	if len(opts.From) > 0 {
+		from = opts.From
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(opts.apply(c).Post(em.GetURL("messages/scheduled").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&scheduledMessageReq{
+			SendTime: sendAt.UnixMilli(),
+			sendMessageReq: sendMessageReq{
+				From:       from,
+				To:         targets,
+				Type:       msg.MessageType(),
+				Body:       msg,
+				Ext:        opts.Ext,
+				RouteType:  opts.RouteType,
+				SyncDevice: opts.SyncDevice,
+				Priority:   opts.priority(),
+			},
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("send scheduled message error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("send scheduled message", res)
+	}
+
+	resp := &RespCommon[*ScheduledMessageResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("send scheduled message error: %w", e)
+	}
+
+	if resp.Data == nil || len(resp.Data.JobId) < 1 {
+		return nil, errors.New("send scheduled message error: job id is empty")
+	}
+
+	return resp.Data, nil
+}
+
+// CancelScheduledMessage 取消尚未发送的定时消息
+// jobId: SendScheduledMessage 返回的定时任务 ID
+func (em *Easemob) CancelScheduledMessage(ctx context.Context, jobId string) error {
+	if len(jobId) < 1 {
+		return errors.New("cancel scheduled message error: job id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("messages/scheduled", jobId)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("cancel scheduled message error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("cancel scheduled message", res)
+	}
+
+	return nil
+}
+
+type ScheduledMessageInfo struct {
+	JobId   string    // 定时任务 ID
+	SendAt  time.Time // 发送时间
+	From    string    // 发送方
+	Targets []string  // 接收方
+	Status  string    // 任务状态, 例如 PENDING, SENT, CANCELED
+	Preview string    // 消息内容预览
+}
+
+type scheduledMessageInfoResp struct {
+	JobId    string   `json:"jobId"`
+	SendTime int64    `json:"send_time"` // Unix 时间戳, 单位为毫秒
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Status   string   `json:"status"`
+	Preview  string   `json:"preview"`
+}
+
+// GetScheduledMessages 分页获取待发送的定时消息
+// pageNum: 页码 (从 1 开始), pageSize: 每页数量
+func (em *Easemob) GetScheduledMessages(ctx context.Context, pageNum, pageSize int) ([]*ScheduledMessageInfo, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL("messages/scheduled")
+	q := url.Values{}
+	if pageNum > 0 {
+		q.Set("pageNum", strconv.Itoa(pageNum))
+	}
+	if pageSize > 0 {
+		q.Set("pageSize", strconv.Itoa(pageSize))
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get scheduled messages error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get scheduled messages", res)
+	}
+
+	resp := &RespCommon[[]*scheduledMessageInfoResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get scheduled messages error: %w", e)
+	}
+
+	infos := make([]*ScheduledMessageInfo, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		infos = append(infos, &ScheduledMessageInfo{
+			JobId:   v.JobId,
+			SendAt:  time.UnixMilli(v.SendTime),
+			From:    v.From,
+			Targets: v.To,
+			Status:  v.Status,
+			Preview: v.Preview,
+		})
+	}
+
+	return infos, nil
+}
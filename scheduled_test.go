@@ -0,0 +1,147 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSendScheduledMessage(t *testing.T) {
+	var body []byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/messages/scheduled", func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"jobId": "job-1"}})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	sendAt := time.Now().Add(time.Hour)
+	resp, e := em.SendScheduledMessage(context.Background(), sendAt, "bot", []string{"alice", "bob"}, &TextBody{Msg: "早上好"}, &MessageOptions{
+		From:      "carol",
+		Ext:       map[string]interface{}{"k": "v"},
+		RouteType: RouteOnline,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if resp.JobId != "job-1" {
+		t.Errorf("unexpected job id: %s", resp.JobId)
+	}
+
+	assertJSON(t, body, `{
+		"send_time": `+strconv.FormatInt(sendAt.UnixMilli(), 10)+`,
+		"from": "carol",
+		"to": ["alice", "bob"],
+		"type": "txt",
+		"body": {"msg": "早上好"},
+		"ext": {"k": "v"},
+		"routetype": "ROUTE_ONLINE"
+	}`)
+
+	// opts 为 nil 时使用 from 参数
+	if _, e := em.SendScheduledMessage(context.Background(), sendAt, "bot", []string{"alice"}, &TextBody{Msg: "hi"}, nil); e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, body, `{"send_time": `+strconv.FormatInt(sendAt.UnixMilli(), 10)+`, "from": "bot", "to": ["alice"], "type": "txt", "body": {"msg": "hi"}}`)
+}
+
+func TestSendScheduledMessageValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	ctx := context.Background()
+	sendAt := time.Now().Add(time.Hour)
+
+	if _, e := em.SendScheduledMessage(ctx, time.Now().Add(30*time.Second), "bot", []string{"alice"}, &TextBody{Msg: "hi"}, nil); e == nil {
+		t.Error("expected error for send time less than ScheduledMessageMinDelay in the future")
+	}
+
+	if _, e := em.SendScheduledMessage(ctx, sendAt, "bot", nil, &TextBody{Msg: "hi"}, nil); e == nil {
+		t.Error("expected error for empty targets")
+	}
+
+	if _, e := em.SendScheduledMessage(ctx, sendAt, "bot", []string{"alice"}, &TextBody{}, nil); e == nil {
+		t.Error("expected error for empty msg")
+	}
+}
+
+func TestSendScheduledMessageEmptyJobId(t *testing.T) {
+	em := newTestEasemob(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{}})
+	}))
+
+	if _, e := em.SendScheduledMessage(context.Background(), time.Now().Add(time.Hour), "bot", []string{"alice"}, &TextBody{Msg: "hi"}, nil); e == nil {
+		t.Error("expected error for empty job id")
+	}
+}
+
+func TestCancelAndGetScheduledMessages(t *testing.T) {
+	var canceled, query string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /org/app/messages/scheduled/{jobId}", func(w http.ResponseWriter, r *http.Request) {
+		canceled = r.PathValue("jobId")
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+	mux.HandleFunc("GET /org/app/messages/scheduled", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [
+			{"jobId": "job-1", "send_time": 1710000000000, "from": "bot", "to": ["alice", "bob"], "status": "PENDING", "preview": "早上好"}
+		]}`))
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	if e := em.CancelScheduledMessage(ctx, "job-1"); e != nil {
+		t.Fatal(e)
+	}
+
+	if canceled != "job-1" {
+		t.Errorf("unexpected canceled job: %s", canceled)
+	}
+
+	infos, e := em.GetScheduledMessages(ctx, 2, 20)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if query != "pageNum=2&pageSize=20" {
+		t.Errorf("unexpected query: %s", query)
+	}
+
+	want := &ScheduledMessageInfo{
+		JobId:   "job-1",
+		SendAt:  time.UnixMilli(1710000000000),
+		From:    "bot",
+		Targets: []string{"alice", "bob"},
+		Status:  "PENDING",
+		Preview: "早上好",
+	}
+	if len(infos) != 1 || !reflect.DeepEqual(infos[0], want) {
+		t.Errorf("unexpected scheduled messages: %+v", infos)
+	}
+
+	if e := em.CancelScheduledMessage(ctx, ""); e == nil {
+		t.Error("expected error for empty job id")
+	}
+}
+
+func TestCancelScheduledMessageNotFound(t *testing.T) {
+	em := newTestEasemob(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "resource_not_found", "job not found")
+	}))
+
+	if e := em.CancelScheduledMessage(context.Background(), "job-1"); !errors.Is(e, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", e)
+	}
+}
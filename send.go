@@ -0,0 +1,514 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"uw/ureq"
+)
+
+const (
+	MessageUsersMaxTargets      = 600 // 发送单聊消息时单次最多的接收方数量
+	MessageChatGroupsMaxTargets = 3   // 发送群聊消息时单次最多的群组数量
+	MessageChatRoomsMaxTargets  = 3   // 发送聊天室消息时单次最多的聊天室数量
+)
+
+// TargetType 消息接收方类型
+type TargetType string
+
+const (
+	TargetUsers      TargetType = "users"      // 单聊
+	TargetChatGroups TargetType = "chatgroups" // 群聊
+	TargetChatRooms  TargetType = "chatrooms"  // 聊天室
+)
+
+// maxTargets 单次发送最多的接收方数量
+func (t TargetType) maxTargets() int {
+	switch t {
+	case TargetUsers:
+		return MessageUsersMaxTargets
+	case TargetChatGroups:
+		return MessageChatGroupsMaxTargets
+	case TargetChatRooms:
+		return MessageChatRoomsMaxTargets
+	}
+
+	return 0
+}
+
+// MessageBody 消息体, 由 TextBody, ImageBody 等实现
+type MessageBody interface {
+	// MessageType 消息类型, 即请求中的 type 字段: txt, img, audio, video, file, loc, cmd, custom
+	MessageType() string
+}
+
+// messageBodyPreparer 发送前需要校验或补全字段的消息体
+type messageBodyPreparer interface {
+	prepare(em *Easemob) error
+}
+
+// prepareMessageBody 校验并补全消息体, 返回实际发送的消息体
+// prepare 定义在指针类型上, 值类型的消息体 (例如 LocationBody{}) 会先复制为指针, 保证同样经过校验
+func (em *Easemob) prepareMessageBody(body MessageBody) (MessageBody, error) {
+	switch b := body.(type) {
+	case TextBody:
+		body = &b
+	case ImageBody:
+		body = &b
+	case AudioBody:
+		body = &b
+	case VideoBody:
+		body = &b
+	case FileBody:
+		body = &b
+	case LocationBody:
+		body = &b
+	case CmdBody:
+		body = &b
+	case CustomBody:
+		body = &b
+	}
+
+	if p, ok := body.(messageBodyPreparer); ok {
+		if e := p.prepare(em); e != nil {
+			return nil, e
+		}
+	}
+
+	return body, nil
+}
+
+type SendMessageResult struct {
+	MsgIds map[string]string // 接收方到消息 ID 的映射
+}
+
+type sendMessageReq struct {
+	From       string                 `json:"from,omitempty"`
+	To         []string               `json:"to"`
+	Type       string                 `json:"type"`
+	Body       MessageBody            `json:"body"`
+	Ext        map[string]interface{} `json:"ext,omitempty"`
+	RouteType  string                 `json:"routetype,omitempty"`
+	SyncDevice bool                   `json:"sync_device,omitempty"`
+
+	ChatroomMsgLevel ChatroomMsgLevel `json:"chatroom_msg_level,omitempty"`
+	Priority         MessagePriority  `json:"priority,omitempty"`
+	NeedGroupAck     bool             `json:"need_group_ack,omitempty"`
+	TranslateTo      []string         `json:"translations,omitempty"`
+}
+
+// SendMessage 发送消息, 根据 target 发送到单聊, 群聊或聊天室
+// body 可以传值或指针, 传指针时 (例如 &ImageBody{}) 补全的字段会写回原消息体
+// target: 接收方类型, from: 发送方, 为空时为 admin, to: 接收方 (用户, 群组或聊天室 ID), body: 消息体
+func (em *Easemob) SendMessage(ctx context.Context, target TargetType, from string, to []string, body MessageBody, opts ...MessageOption) (*SendMessageResult, error) {
+	maxTargets := target.maxTargets()
+	if maxTargets < 1 {
+		return nil, fmt.Errorf("send message error: invalid target type %q", target)
+	}
+
+	if len(to) < 1 || len(to) > maxTargets {
+		return nil, fmt.Errorf("send message error: to length must be in [1, %d]", maxTargets)
+	}
+
+	for _, v := range to {
+		if len(strings.TrimSpace(v)) < 1 {
+			return nil, errors.New("send message error: to contains empty target")
+		}
+	}
+
+	if body == nil {
+		return nil, errors.New("send message error: body is nil")
+	}
+
+	body, e := em.prepareMessageBody(body)
+	if e != nil {
+		return nil, fmt.Errorf("send message error: %w", e)
+	}
+
+	o := newMessageOptions(opts)
+	if len(o.ChatroomMsgLevel) > 0 && target != TargetChatRooms {
+		return nil, errors.New("send message error: chatroom msg level only applies to chatrooms")
+	}
+
+	if o.NeedGroupAck && target != TargetChatGroups {
+		return nil, errors.New("send message error: group ack only applies to chatgroups")
+	}
+
+	if len(o.TranslateTo) > 0 {
+		if _, ok := body.(*TextBody); !ok {
+			return nil, errors.New("send message error: translation only applies to text messages")
+		}
+
+		if e := validateTranslationLanguages(o.TranslateTo); e != nil {
+			return nil, fmt.Errorf("send message error: %w", e)
+		}
+	}
+
+	if len(o.Priority) > 0 && o.Priority != PriorityNormal && o.Priority != PriorityHigh {
+		return nil, fmt.Errorf("send message error: invalid priority %q", o.Priority)
+	}
+
+	if len(o.From) > 0 {
+		from = o.From
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(o.apply(c).Post(em.GetMessageURL(string(target))).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&sendMessageReq{
+			From:       from,
+			To:         to,
+			Type:       body.MessageType(),
+			Body:       body,
+			Ext:        o.Ext,
+			RouteType:  o.RouteType,
+			SyncDevice: o.SyncDevice,
+
+			ChatroomMsgLevel: o.ChatroomMsgLevel,
+			Priority:         o.priority(),
+			NeedGroupAck:     o.NeedGroupAck,
+			TranslateTo:      o.TranslateTo,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("send message error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("send message", res)
+	}
+
+	resp := &RespCommon[map[string]string]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("send message error: %w", e)
+	}
+
+	if resp.Data == nil {
+		resp.Data = map[string]string{}
+	}
+
+	return &SendMessageResult{MsgIds: resp.Data}, nil
+}
+
+// SendGroupMessage 发送群聊消息, 返回群组 ID 到消息 ID 的映射
+// 发送方不需要是群成员 (例如管理员账号), 因此不会在客户端校验成员关系
+// from: 发送方, 为空时为 admin, groupIDs: 群组 ID, 最多 MessageChatGroupsMaxTargets 个, body: 消息体
+func (em *Easemob) SendGroupMessage(ctx context.Context, from string, groupIDs []string, body MessageBody, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetChatGroups, from, groupIDs, body, opts...)
+}
+
+// SendChatroomMessage 发送聊天室消息, 返回聊天室 ID 到消息 ID 的映射
+// 弹幕等消息量大的场景可以使用 WithChatroomMsgLevel 设置消息优先级
+// from: 发送方, 为空时为 admin, roomIDs: 聊天室 ID, 最多 MessageChatRoomsMaxTargets 个, body: 消息体
+func (em *Easemob) SendChatroomMessage(ctx context.Context, from string, roomIDs []string, body MessageBody, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetChatRooms, from, roomIDs, body, opts...)
+}
+
+type TextBody struct {
+	Msg string `json:"msg"` // 消息内容
+}
+
+func (TextBody) MessageType() string { return "txt" }
+
+func (b *TextBody) prepare(*Easemob) error {
+	if len(b.Msg) < 1 {
+		return errors.New("text is empty")
+	}
+
+	return nil
+}
+
+// SendTextMessage 发送单聊文本消息
+// from: 发送方, 为空时为 admin, to: 接收方, 最多 MessageUsersMaxTargets 个, text: 消息内容
+func (em *Easemob) SendTextMessage(ctx context.Context, from string, to []string, text string, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetUsers, from, to, &TextBody{Msg: text}, opts...)
+}
+
+type ImageSize struct {
+	Width  int `json:"width"`  // 图片宽度, 单位为像素
+	Height int `json:"height"` // 图片高度, 单位为像素
+}
+
+type ImageBody struct {
+	Filename   string     `json:"filename"`              // 图片名称
+	Url        string     `json:"url"`                   // 图片的 URL 地址
+	Secret     string     `json:"secret,omitempty"`      // 图片的访问密钥, 上传文件后返回的 share-secret
+	Size       *ImageSize `json:"size,omitempty"`        // 图片尺寸
+	FileLength int64      `json:"file_length,omitempty"` // 图片大小, 单位为字节
+
+	// 已上传文件的 UUID, Url 为空时会根据该值生成文件的下载地址
+	FileUUID string `json:"-"`
+}
+
+func (ImageBody) MessageType() string { return "img" }
+
+func (b *ImageBody) prepare(em *Easemob) error {
+	return em.resolveFileUrl(&b.Url, b.FileUUID)
+}
+
+// SendImageMessage 发送单聊图片消息
+// Url 和 FileUUID 至少需要设置一个
+// from: 发送方, 为空时为 admin, to: 接收方, img: 图片消息内容
+func (em *Easemob) SendImageMessage(ctx context.Context, from string, to []string, img ImageBody, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetUsers, from, to, &img, opts...)
+}
+
+// resolveFileUrl url 为空时根据已上传文件的 UUID 生成下载地址
+func (em *Easemob) resolveFileUrl(url *string, fileUUID string) error {
+	if len(*url) > 0 {
+		return nil
+	}
+
+	if len(fileUUID) < 1 {
+		return errors.New("url or uploaded file uuid is required")
+	}
+
+	*url = em.GetURL(path.Join("chatfiles", fileUUID)).String()
+	return nil
+}
+
+type AudioBody struct {
+	Filename   string `json:"filename"`              // 语音文件名称
+	Url        string `json:"url"`                   // 语音文件的 URL 地址
+	Secret     string `json:"secret,omitempty"`      // 语音文件的访问密钥
+	Length     int    `json:"length"`                // 语音时长, 单位为秒
+	FileLength int64  `json:"file_length,omitempty"` // 语音文件大小, 单位为字节
+
+	// 已上传文件的 UUID, Url 为空时会根据该值生成文件的下载地址
+	FileUUID string `json:"-"`
+}
+
+func (AudioBody) MessageType() string { return "audio" }
+
+func (b *AudioBody) prepare(em *Easemob) error {
+	return em.resolveFileUrl(&b.Url, b.FileUUID)
+}
+
+type VideoBody struct {
+	Filename    string     `json:"filename"`               // 视频文件名称
+	Url         string     `json:"url"`                    // 视频文件的 URL 地址
+	Secret      string     `json:"secret,omitempty"`       // 视频文件的访问密钥
+	Length      int        `json:"length"`                 // 视频时长, 单位为秒
+	FileLength  int64      `json:"file_length,omitempty"`  // 视频文件大小, 单位为字节
+	Size        *ImageSize `json:"size,omitempty"`         // 视频尺寸
+	Thumb       string     `json:"thumb,omitempty"`        // 视频缩略图的 URL 地址, 可选
+	ThumbSecret string     `json:"thumb_secret,omitempty"` // 视频缩略图的访问密钥, 可选
+
+	// 已上传文件的 UUID, Url 为空时会根据该值生成文件的下载地址
+	FileUUID string `json:"-"`
+}
+
+func (VideoBody) MessageType() string { return "video" }
+
+func (b *VideoBody) prepare(em *Easemob) error {
+	return em.resolveFileUrl(&b.Url, b.FileUUID)
+}
+
+// SendAudioMessage 发送单聊语音消息
+// from: 发送方, 为空时为 admin, to: 接收方, audio: 语音消息内容
+func (em *Easemob) SendAudioMessage(ctx context.Context, from string, to []string, audio AudioBody, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetUsers, from, to, &audio, opts...)
+}
+
+// SendVideoMessage 发送单聊视频消息
+// from: 发送方, 为空时为 admin, to: 接收方, video: 视频消息内容
+func (em *Easemob) SendVideoMessage(ctx context.Context, from string, to []string, video VideoBody, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetUsers, from, to, &video, opts...)
+}
+
+type FileBody struct {
+	Filename   string `json:"filename"`              // 文件名称
+	Url        string `json:"url"`                   // 文件的 URL 地址
+	Secret     string `json:"secret,omitempty"`      // 文件的访问密钥
+	FileLength int64  `json:"file_length,omitempty"` // 文件大小, 单位为字节
+
+	// 已上传文件的 UUID, Url 为空时会根据该值生成文件的下载地址
+	FileUUID string `json:"-"`
+}
+
+func (FileBody) MessageType() string { return "file" }
+
+func (b *FileBody) prepare(em *Easemob) error {
+	return em.resolveFileUrl(&b.Url, b.FileUUID)
+}
+
+type LocationBody struct {
+	Lat          float64 `json:"lat"`                    // 纬度, 范围 [-90, 90]
+	Lng          float64 `json:"lng"`                    // 经度, 范围 [-180, 180]
+	Addr         string  `json:"addr"`                   // 地址
+	BuildingName string  `json:"buildingName,omitempty"` // 建筑物名称, 可选
+}
+
+func (LocationBody) MessageType() string { return "loc" }
+
+func (b *LocationBody) prepare(*Easemob) error {
+	if b.Lat < -90 || b.Lat > 90 {
+		return fmt.Errorf("latitude %v out of range", b.Lat)
+	}
+
+	if b.Lng < -180 || b.Lng > 180 {
+		return fmt.Errorf("longitude %v out of range", b.Lng)
+	}
+
+	return nil
+}
+
+// SendFileMessage 发送单聊文件消息
+// from: 发送方, 为空时为 admin, to: 接收方, file: 文件消息内容
+func (em *Easemob) SendFileMessage(ctx context.Context, from string, to []string, file FileBody, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetUsers, from, to, &file, opts...)
+}
+
+// SendLocationMessage 发送单聊位置消息
+// from: 发送方, 为空时为 admin, to: 接收方, loc: 位置消息内容
+func (em *Easemob) SendLocationMessage(ctx context.Context, from string, to []string, loc LocationBody, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetUsers, from, to, &loc, opts...)
+}
+
+type CmdBody struct {
+	Action string `json:"action"` // 命令内容
+}
+
+func (CmdBody) MessageType() string { return "cmd" }
+
+func (b *CmdBody) prepare(*Easemob) error {
+	if len(b.Action) < 1 {
+		return errors.New("action is empty")
+	}
+
+	return nil
+}
+
+// SendCmdMessage 发送单聊透传消息, 透传消息不会在界面上展示, 通常用于驱动客户端状态
+// 透传消息一般不需要离线存储, 可以配合 WithOnlineOnly 使用, 参数可通过 WithExt 传递
+// from: 发送方, 为空时为 admin, to: 接收方, action: 命令内容
+func (em *Easemob) SendCmdMessage(ctx context.Context, from string, to []string, action string, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetUsers, from, to, &CmdBody{Action: action}, opts...)
+}
+
+// CustomExtsMaxKeys 自定义消息扩展字段的最大键数量
+const CustomExtsMaxKeys = 16
+
+// customEventPattern 自定义事件类型的字符集和长度限制
+var customEventPattern = regexp.MustCompile(`^[a-zA-Z0-9\-_/.]{1,32}$`)
+
+type CustomBody struct {
+	CustomEvent string            `json:"customEvent"`          // 自定义事件类型, 由字母, 数字, -, _, /, . 组成, 最长 32 个字符
+	CustomExts  map[string]string `json:"customExts,omitempty"` // 自定义事件属性, 最多 CustomExtsMaxKeys 个
+}
+
+func (CustomBody) MessageType() string { return "custom" }
+
+func (b *CustomBody) prepare(*Easemob) error {
+	if !customEventPattern.MatchString(b.CustomEvent) {
+		return fmt.Errorf("invalid custom event %q", b.CustomEvent)
+	}
+
+	if len(b.CustomExts) > CustomExtsMaxKeys {
+		return fmt.Errorf("custom exts must not exceed %d keys", CustomExtsMaxKeys)
+	}
+
+	return nil
+}
+
+// SendCustomMessage 发送单聊自定义消息
+// from: 发送方, 为空时为 admin, to: 接收方, customEvent: 自定义事件类型, customExts: 自定义事件属性
+func (em *Easemob) SendCustomMessage(ctx context.Context, from string, to []string, customEvent string, customExts map[string]string, opts ...MessageOption) (*SendMessageResult, error) {
+	return em.SendMessage(ctx, TargetUsers, from, to, &CustomBody{
+		CustomEvent: customEvent,
+		CustomExts:  customExts,
+	}, opts...)
+}
+
+// ErrBroadcastQuotaExceeded 全员广播超过调用频率或每日次数限制
+var ErrBroadcastQuotaExceeded = errors.New("broadcast quota exceeded")
+
+type BroadcastResult struct {
+	Id string `json:"id"` // 广播消息 ID
+}
+
+type broadcastMessageReq struct {
+	From       string                 `json:"from,omitempty"`
+	Type       string                 `json:"type"`
+	Body       MessageBody            `json:"body"`
+	Ext        map[string]interface{} `json:"ext,omitempty"`
+	RouteType  string                 `json:"routetype,omitempty"`
+	SyncDevice bool                   `json:"sync_device,omitempty"`
+	Priority   MessagePriority        `json:"priority,omitempty"`
+}
+
+// BroadcastMessage 向 App 下的所有用户广播消息, 用于系统通知
+// 该接口的调用频率和每日次数都有严格限制, 本地默认限流为 1 次/分钟 (参见 LimiterBroadcastMessages),
+// 超过服务端限制时返回的错误满足 errors.Is(e, ErrBroadcastQuotaExceeded)
+// from: 发送方, 为空时为 admin, body: 消息体
+func (em *Easemob) BroadcastMessage(ctx context.Context, from string, body MessageBody, opts ...MessageOption) (*BroadcastResult, error) {
+	if body == nil {
+		return nil, errors.New("broadcast message error: body is nil")
+	}
+
+	body, e := em.prepareMessageBody(body)
+	if e != nil {
+		return nil, fmt.Errorf("broadcast message error: %w", e)
+	}
+
+	o := newMessageOptions(opts)
+	if len(o.ChatroomMsgLevel) > 0 || o.NeedGroupAck {
+		return nil, errors.New("broadcast message error: chatroom msg level and group ack do not apply to broadcast")
+	}
+
+	if len(o.From) > 0 {
+		from = o.From
+	}
+
+	if e := em.getEndpointLimiter(ctx, LimiterBroadcastMessages); e != nil {
+		return nil, e
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(o.apply(c).Post(em.GetURL("messages/users/broadcast").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&broadcastMessageReq{
+			From:       from,
+			Type:       body.MessageType(),
+			Body:       body,
+			Ext:        o.Ext,
+			RouteType:  o.RouteType,
+			SyncDevice: o.SyncDevice,
+			Priority:   o.priority(),
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("broadcast message error: %w", e)
+	}
+
+	if !res.OK() {
+		e := newResponseError("broadcast message", res)
+		if errors.Is(e, ErrTooManyRequests) {
+			return nil, fmt.Errorf("%w: %w", ErrBroadcastQuotaExceeded, e)
+		}
+
+		return nil, e
+	}
+
+	resp := &RespCommon[*BroadcastResult]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("broadcast message error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return &BroadcastResult{}, nil
+	}
+
+	return resp.Data, nil
+}
@@ -0,0 +1,772 @@
+package easemob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// messageRecorder 记录发送消息接口收到的请求, 并为每个接收方返回 "msg-" + 接收方 的消息 ID
+type messageRecorder struct {
+	mu       sync.Mutex
+	targets  []string
+	bodies   [][]byte
+	requests []*http.Request
+}
+
+func (m *messageRecorder) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/messages/{target}", func(w http.ResponseWriter, r *http.Request) {
+		body, e := io.ReadAll(r.Body)
+		if e != nil {
+			t.Error(e)
+		}
+
+		m.mu.Lock()
+		m.targets = append(m.targets, r.PathValue("target"))
+		m.bodies = append(m.bodies, body)
+		m.requests = append(m.requests, r)
+		m.mu.Unlock()
+
+		req := &struct {
+			To []string `json:"to"`
+		}{}
+		if e := json.Unmarshal(body, req); e != nil {
+			t.Error(e)
+		}
+
+		data := map[string]string{}
+		for _, v := range req.To {
+			data[v] = "msg-" + v
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+	})
+
+	return mux
+}
+
+// last 最后一次请求的请求体
+func (m *messageRecorder) last(t *testing.T) []byte {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.bodies) < 1 {
+		t.Fatal("no message sent")
+	}
+
+	return m.bodies[len(m.bodies)-1]
+}
+
+func TestSendTextMessage(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+
+	result, e := em.SendTextMessage(context.Background(), "bot", []string{"alice", "bob"}, "你好",
+		WithExt(map[string]interface{}{"order_id": "o-1"}),
+		WithOnlineOnly(),
+		WithSyncDevice())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["alice", "bob"],
+		"type": "txt",
+		"body": {"msg": "你好"},
+		"ext": {"order_id": "o-1"},
+		"routetype": "ROUTE_ONLINE",
+		"sync_device": true
+	}`)
+
+	if m.targets[0] != "users" {
+		t.Errorf("unexpected target: %s", m.targets[0])
+	}
+
+	want := map[string]string{"alice": "msg-alice", "bob": "msg-bob"}
+	if !reflect.DeepEqual(result.MsgIds, want) {
+		t.Errorf("unexpected msg ids: %v", result.MsgIds)
+	}
+}
+
+func TestSendTextMessageIdempotencyKey(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+
+	if _, e := em.SendTextMessage(context.Background(), "", []string{"alice"}, "hi", WithIdempotencyKey("key-1")); e != nil {
+		t.Fatal(e)
+	}
+
+	if v := m.requests[0].Header.Get(HeaderRequestID); v != "key-1" {
+		t.Errorf("unexpected request id header: %q", v)
+	}
+
+	// from 为空时不发送, 由服务端使用 admin
+	assertJSON(t, m.last(t), `{"to": ["alice"], "type": "txt", "body": {"msg": "hi"}}`)
+}
+
+func TestIdempotencyKeyNotShared(t *testing.T) {
+	var userHeader http.Header
+
+	m := &messageRecorder{}
+	mux := http.NewServeMux()
+	mux.Handle("POST /org/app/messages/{target}", m.handler(t))
+	mux.HandleFunc("GET /org/app/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		userHeader = r.Header.Clone()
+		slowHandler(0)(w, r)
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	if _, e := em.SendTextMessage(ctx, "bot", []string{"alice"}, "hi", WithIdempotencyKey("key-1")); e != nil {
+		t.Fatal(e)
+	}
+
+	if _, e := em.SendTextMessage(ctx, "bot", []string{"alice"}, "hi"); e != nil {
+		t.Fatal(e)
+	}
+
+	if _, e := em.GetUser(ctx, "alice"); e != nil {
+		t.Fatal(e)
+	}
+
+	// 幂等键只作用于设置它的请求, 不会出现在之后的任何请求中
+	if v := m.requests[1].Header.Get(HeaderRequestID); v != "" {
+		t.Errorf("request id header leaked into the next message: %q", v)
+	}
+
+	if v := userHeader.Get(HeaderRequestID); v != "" {
+		t.Errorf("request id header leaked into get user: %q", v)
+	}
+}
+
+func TestSendTextMessageValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	tooMany := make([]string, MessageUsersMaxTargets+1)
+	for i := range tooMany {
+		tooMany[i] = "u"
+	}
+
+	cases := map[string]struct {
+		to   []string
+		text string
+	}{
+		"no recipients":  {nil, "hi"},
+		"too many":       {tooMany, "hi"},
+		"blank receiver": {[]string{"alice", " "}, "hi"},
+		"empty text":     {[]string{"alice"}, ""},
+	}
+
+	for name, c := range cases {
+		if _, e := em.SendTextMessage(context.Background(), "bot", c.to, c.text); e == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSendImageMessage(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+
+	_, e := em.SendImageMessage(context.Background(), "bot", []string{"alice"}, ImageBody{
+		Filename:   "qrcode.png",
+		Url:        "https://a1.easemob.com/org/app/chatfiles/uuid-1",
+		Secret:     "secret-1",
+		Size:       &ImageSize{Width: 480, Height: 480},
+		FileLength: 12345,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["alice"],
+		"type": "img",
+		"body": {
+			"filename": "qrcode.png",
+			"url": "https://a1.easemob.com/org/app/chatfiles/uuid-1",
+			"secret": "secret-1",
+			"size": {"width": 480, "height": 480},
+			"file_length": 12345
+		}
+	}`)
+}
+
+func TestSendImageMessageUploadedFile(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+
+	// 上传文件返回的 UUID 和 share-secret 可以直接使用
+	_, e := em.SendImageMessage(context.Background(), "bot", []string{"alice"}, ImageBody{
+		Filename: "qrcode.png",
+		Secret:   "secret-1",
+		FileUUID: "uuid-1",
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	body := &struct {
+		Body map[string]interface{} `json:"body"`
+	}{}
+	if e := json.Unmarshal(m.last(t), body); e != nil {
+		t.Fatal(e)
+	}
+
+	if want := em.GetURL("chatfiles/uuid-1").String(); body.Body["url"] != want {
+		t.Errorf("unexpected url: %v, want %s", body.Body["url"], want)
+	}
+
+	if _, ok := body.Body["size"]; ok {
+		t.Error("size should be omitted when unset")
+	}
+}
+
+func TestSendImageMessageMissingUrl(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	if _, e := em.SendImageMessage(context.Background(), "bot", []string{"alice"}, ImageBody{Filename: "qrcode.png"}); e == nil {
+		t.Error("expected error when neither url nor file uuid is set")
+	}
+}
+
+func TestSendAudioVideoMessage(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+	ctx := context.Background()
+
+	_, e := em.SendAudioMessage(ctx, "bot", []string{"alice"}, AudioBody{
+		Filename:   "note.amr",
+		Url:        "https://example.com/note.amr",
+		Secret:     "s-audio",
+		Length:     12,
+		FileLength: 2048,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["alice"],
+		"type": "audio",
+		"body": {"filename": "note.amr", "url": "https://example.com/note.amr", "secret": "s-audio", "length": 12, "file_length": 2048}
+	}`)
+
+	_, e = em.SendVideoMessage(ctx, "bot", []string{"alice"}, VideoBody{
+		Filename:    "clip.mp4",
+		Url:         "https://example.com/clip.mp4",
+		Secret:      "s-video",
+		Length:      30,
+		FileLength:  1 << 20,
+		Size:        &ImageSize{Width: 720, Height: 1280},
+		Thumb:       "https://example.com/clip.jpg",
+		ThumbSecret: "s-thumb",
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["alice"],
+		"type": "video",
+		"body": {
+			"filename": "clip.mp4",
+			"url": "https://example.com/clip.mp4",
+			"secret": "s-video",
+			"length": 30,
+			"file_length": 1048576,
+			"size": {"width": 720, "height": 1280},
+			"thumb": "https://example.com/clip.jpg",
+			"thumb_secret": "s-thumb"
+		}
+	}`)
+
+	// 缩略图是可选的, 未设置时不出现在请求中
+	_, e = em.SendVideoMessage(ctx, "bot", []string{"alice"}, VideoBody{
+		Filename: "clip.mp4",
+		Url:      "https://example.com/clip.mp4",
+		Length:   30,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["alice"],
+		"type": "video",
+		"body": {"filename": "clip.mp4", "url": "https://example.com/clip.mp4", "length": 30}
+	}`)
+}
+
+func TestSendFileLocationMessage(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+	ctx := context.Background()
+
+	result, e := em.SendFileMessage(ctx, "bot", []string{"alice"}, FileBody{
+		Filename:   "report.pdf",
+		Url:        "https://example.com/report.pdf",
+		Secret:     "s-file",
+		FileLength: 4096,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["alice"],
+		"type": "file",
+		"body": {"filename": "report.pdf", "url": "https://example.com/report.pdf", "secret": "s-file", "file_length": 4096}
+	}`)
+
+	if result.MsgIds["alice"] != "msg-alice" {
+		t.Errorf("unexpected msg ids: %v", result.MsgIds)
+	}
+
+	_, e = em.SendLocationMessage(ctx, "bot", []string{"alice"}, LocationBody{
+		Lat:          39.9042,
+		Lng:          116.4074,
+		Addr:         "北京市东城区",
+		BuildingName: "天安门",
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["alice"],
+		"type": "loc",
+		"body": {"lat": 39.9042, "lng": 116.4074, "addr": "北京市东城区", "buildingName": "天安门"}
+	}`)
+}
+
+func TestSendLocationMessageOutOfRange(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	for _, loc := range []LocationBody{
+		{Lat: 90.5, Lng: 0},
+		{Lat: -91, Lng: 0},
+		{Lat: 0, Lng: 180.1},
+	} {
+		if _, e := em.SendLocationMessage(context.Background(), "bot", []string{"alice"}, loc); e == nil {
+			t.Errorf("expected error for %+v", loc)
+		}
+	}
+}
+
+func TestSendCmdMessage(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+	ctx := context.Background()
+
+	if _, e := em.SendCmdMessage(ctx, "bot", []string{"alice"}, "refresh_order"); e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{"from": "bot", "to": ["alice"], "type": "cmd", "body": {"action": "refresh_order"}}`)
+
+	_, e := em.SendCmdMessage(ctx, "bot", []string{"alice"}, "refresh_order",
+		WithOnlineOnly(),
+		WithExt(map[string]interface{}{"order_id": "o-1"}))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["alice"],
+		"type": "cmd",
+		"body": {"action": "refresh_order"},
+		"ext": {"order_id": "o-1"},
+		"routetype": "ROUTE_ONLINE"
+	}`)
+
+	// action 解析回 CmdBody 后保持不变
+	req := &struct {
+		Body CmdBody `json:"body"`
+	}{}
+	if e := json.Unmarshal(m.last(t), req); e != nil {
+		t.Fatal(e)
+	}
+
+	if req.Body.Action != "refresh_order" {
+		t.Errorf("unexpected action: %q", req.Body.Action)
+	}
+}
+
+func TestSendCustomMessage(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+	ctx := context.Background()
+
+	_, e := em.SendCustomMessage(ctx, "bot", []string{"alice"}, "order/card.v1", map[string]string{"order_id": "o-1", "price": "9.9"})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["alice"],
+		"type": "custom",
+		"body": {"customEvent": "order/card.v1", "customExts": {"order_id": "o-1", "price": "9.9"}}
+	}`)
+
+	// 群组和聊天室共用 SendMessage
+	body := &CustomBody{CustomEvent: "gift"}
+	for _, target := range []TargetType{TargetChatGroups, TargetChatRooms} {
+		if _, e := em.SendMessage(ctx, target, "bot", []string{"x1"}, body); e != nil {
+			t.Fatal(e)
+		}
+
+		assertJSON(t, m.last(t), `{"from": "bot", "to": ["x1"], "type": "custom", "body": {"customEvent": "gift"}}`)
+	}
+
+	if m.targets[1] != "chatgroups" || m.targets[2] != "chatrooms" {
+		t.Errorf("unexpected targets: %v", m.targets)
+	}
+}
+
+func TestSendCustomMessageValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	tooManyExts := map[string]string{}
+	for i := 0; i <= CustomExtsMaxKeys; i++ {
+		tooManyExts[string(rune('a'+i))] = "v"
+	}
+
+	cases := map[string]struct {
+		event string
+		exts  map[string]string
+	}{
+		"empty event":    {"", nil},
+		"invalid char":   {"order card", nil},
+		"non ascii":      {"订单", nil},
+		"event too long": {"abcdefghijklmnopqrstuvwxyz0123456", nil},
+		"too many exts":  {"order", tooManyExts},
+	}
+
+	for name, c := range cases {
+		if _, e := em.SendCustomMessage(context.Background(), "bot", []string{"alice"}, c.event, c.exts); e == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSendMessageBodiesAndTargets(t *testing.T) {
+	bodies := []struct {
+		body MessageBody
+		want string // 请求中的 type 和 body
+	}{
+		{&TextBody{Msg: "hi"}, `"type": "txt", "body": {"msg": "hi"}`},
+		{&ImageBody{Filename: "a.png", Url: "https://x/a.png"}, `"type": "img", "body": {"filename": "a.png", "url": "https://x/a.png"}`},
+		{&AudioBody{Filename: "a.amr", Url: "https://x/a.amr", Length: 3}, `"type": "audio", "body": {"filename": "a.amr", "url": "https://x/a.amr", "length": 3}`},
+		{&VideoBody{Filename: "a.mp4", Url: "https://x/a.mp4", Length: 5}, `"type": "video", "body": {"filename": "a.mp4", "url": "https://x/a.mp4", "length": 5}`},
+		{&FileBody{Filename: "a.pdf", Url: "https://x/a.pdf"}, `"type": "file", "body": {"filename": "a.pdf", "url": "https://x/a.pdf"}`},
+		{&LocationBody{Lat: 1.5, Lng: 2.5, Addr: "here"}, `"type": "loc", "body": {"lat": 1.5, "lng": 2.5, "addr": "here"}`},
+		{&CmdBody{Action: "sync"}, `"type": "cmd", "body": {"action": "sync"}`},
+		{&CustomBody{CustomEvent: "gift"}, `"type": "custom", "body": {"customEvent": "gift"}`},
+	}
+
+	targets := []TargetType{TargetUsers, TargetChatGroups, TargetChatRooms}
+
+	for _, b := range bodies {
+		for _, target := range targets {
+			t.Run(b.body.MessageType()+"/"+string(target), func(t *testing.T) {
+				m := &messageRecorder{}
+				em := newTestEasemob(t, m.handler(t))
+
+				result, e := em.SendMessage(context.Background(), target, "bot", []string{"x1", "x2"}, b.body)
+				if e != nil {
+					t.Fatal(e)
+				}
+
+				if m.targets[0] != string(target) {
+					t.Errorf("unexpected endpoint: %s", m.targets[0])
+				}
+
+				assertJSON(t, m.last(t), `{"from": "bot", "to": ["x1", "x2"], `+b.want+`}`)
+
+				if len(result.MsgIds) != 2 || result.MsgIds["x2"] != "msg-x2" {
+					t.Errorf("unexpected msg ids: %v", result.MsgIds)
+				}
+			})
+		}
+	}
+}
+
+func TestSendMessageValueBodyValidation(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+
+	for _, body := range []MessageBody{
+		TextBody{},
+		ImageBody{Filename: "a.png"},
+		LocationBody{Lat: 91},
+		CmdBody{},
+		CustomBody{CustomEvent: "bad event"},
+	} {
+		if _, e := em.SendMessage(context.Background(), TargetUsers, "bot", []string{"x1"}, body); e == nil {
+			t.Errorf("expected error for %T %+v", body, body)
+		}
+	}
+
+	if len(m.targets) > 0 {
+		t.Errorf("unexpected requests: %v", m.targets)
+	}
+}
+
+func TestSendMessageValueBody(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+
+	body := ImageBody{Filename: "a.png", FileUUID: "uuid-1"}
+	if _, e := em.SendMessage(context.Background(), TargetUsers, "bot", []string{"x1"}, body); e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{"from": "bot", "to": ["x1"], "type": "img", "body": {"filename": "a.png", "url": "`+em.GetURL("chatfiles/uuid-1").String()+`"}}`)
+
+	if len(body.Url) > 0 {
+		t.Errorf("value body should not be modified: %+v", body)
+	}
+}
+
+func TestSendMessageInvalidTarget(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	if _, e := em.SendMessage(context.Background(), "broadcast", "bot", []string{"x1"}, &TextBody{Msg: "hi"}); e == nil {
+		t.Error("expected error for invalid target type")
+	}
+
+	if _, e := em.SendMessage(context.Background(), TargetUsers, "bot", []string{"x1"}, nil); e == nil {
+		t.Error("expected error for nil body")
+	}
+}
+
+func TestSendGroupMessage(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+
+	result, e := em.SendGroupMessage(context.Background(), "admin-bot", []string{"g1", "g2", "g3"}, &TextBody{Msg: "公告"})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if m.targets[0] != "chatgroups" {
+		t.Errorf("unexpected endpoint: %s", m.targets[0])
+	}
+
+	assertJSON(t, m.last(t), `{"from": "admin-bot", "to": ["g1", "g2", "g3"], "type": "txt", "body": {"msg": "公告"}}`)
+
+	want := map[string]string{"g1": "msg-g1", "g2": "msg-g2", "g3": "msg-g3"}
+	if !reflect.DeepEqual(result.MsgIds, want) {
+		t.Errorf("unexpected msg ids: %v", result.MsgIds)
+	}
+}
+
+func TestSendGroupMessageTooManyGroups(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	_, e := em.SendGroupMessage(context.Background(), "bot", []string{"g1", "g2", "g3", "g4"}, &TextBody{Msg: "hi"})
+	if e == nil {
+		t.Error("expected error for too many groups")
+	}
+}
+
+func TestSendChatroomMessage(t *testing.T) {
+	m := &messageRecorder{}
+	em := newTestEasemob(t, m.handler(t))
+	ctx := context.Background()
+
+	result, e := em.SendChatroomMessage(ctx, "bot", []string{"r1", "r2"}, &TextBody{Msg: "弹幕"},
+		WithChatroomMsgLevel(ChatroomMsgLevelLow))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if m.targets[0] != "chatrooms" {
+		t.Errorf("unexpected endpoint: %s", m.targets[0])
+	}
+
+	assertJSON(t, m.last(t), `{
+		"from": "bot",
+		"to": ["r1", "r2"],
+		"type": "txt",
+		"body": {"msg": "弹幕"},
+		"chatroom_msg_level": "low"
+	}`)
+
+	want := map[string]string{"r1": "msg-r1", "r2": "msg-r2"}
+	if !reflect.DeepEqual(result.MsgIds, want) {
+		t.Errorf("unexpected msg ids: %v", result.MsgIds)
+	}
+
+	// 未设置时不发送该字段
+	if _, e := em.SendChatroomMessage(ctx, "bot", []string{"r1"}, &TextBody{Msg: "hi"}); e != nil {
+		t.Fatal(e)
+	}
+
+	assertJSON(t, m.last(t), `{"from": "bot", "to": ["r1"], "type": "txt", "body": {"msg": "hi"}}`)
+}
+
+func TestSendChatroomMessageValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	ctx := context.Background()
+
+	if _, e := em.SendChatroomMessage(ctx, "bot", []string{"r1", "r2", "r3", "r4"}, &TextBody{Msg: "hi"}); e == nil {
+		t.Error("expected error for too many rooms")
+	}
+
+	// 聊天室消息优先级只对聊天室有效
+	if _, e := em.SendGroupMessage(ctx, "bot", []string{"g1"}, &TextBody{Msg: "hi"}, WithChatroomMsgLevel(ChatroomMsgLevelHigh)); e == nil {
+		t.Error("expected error for chatroom msg level on groups")
+	}
+}
+
+func TestMessageOptions(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []MessageOption
+		want string // 除 to, type, body 以外的顶层字段
+	}{
+		{"none", nil, `"from": "bot"`},
+		{"ext", []MessageOption{WithExt(map[string]interface{}{"k": "v"})}, `"from": "bot", "ext": {"k": "v"}`},
+		{"online only", []MessageOption{WithOnlineOnly()}, `"from": "bot", "routetype": "ROUTE_ONLINE"`},
+		{"sync device", []MessageOption{WithSyncDevice()}, `"from": "bot", "sync_device": true`},
+		{"from", []MessageOption{WithFrom("carol")}, `"from": "carol"`},
+		{"ext merge", []MessageOption{
+			WithExt(map[string]interface{}{"a": 1, "b": "old"}),
+			WithExt(map[string]interface{}{"b": "new", "c": true}),
+		}, `"from": "bot", "ext": {"a": 1, "b": "new", "c": true}`},
+		{"all", []MessageOption{
+			WithExt(map[string]interface{}{"a": 1}),
+			WithOnlineOnly(),
+			WithSyncDevice(),
+			WithFrom("carol"),
+			WithPriority(PriorityHigh),
+			WithIdempotencyKey("key-1"),
+			nil,
+		}, `"from": "carol", "ext": {"a": 1}, "routetype": "ROUTE_ONLINE", "sync_device": true, "priority": "high"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &messageRecorder{}
+			em := newTestEasemob(t, m.handler(t))
+
+			if _, e := em.SendTextMessage(context.Background(), "bot", []string{"alice"}, "hi", c.opts...); e != nil {
+				t.Fatal(e)
+			}
+
+			assertJSON(t, m.last(t), `{"to": ["alice"], "type": "txt", "body": {"msg": "hi"}, `+c.want+`}`)
+		})
+	}
+}
+
+func TestMessageOptionsAllTargets(t *testing.T) {
+	ext := map[string]interface{}{"a": 1}
+
+	for _, target := range []TargetType{TargetUsers, TargetChatGroups, TargetChatRooms} {
+		t.Run(string(target), func(t *testing.T) {
+			m := &messageRecorder{}
+			em := newTestEasemob(t, m.handler(t))
+
+			_, e := em.SendMessage(context.Background(), target, "bot", []string{"x1"}, &TextBody{Msg: "hi"},
+				WithExt(ext), WithExt(map[string]interface{}{"b": "v"}), WithOnlineOnly(), WithSyncDevice(), WithFrom("carol"))
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if m.targets[0] != string(target) {
+				t.Errorf("unexpected endpoint: %s", m.targets[0])
+			}
+
+			assertJSON(t, m.last(t), `{
+				"from": "carol",
+				"to": ["x1"],
+				"type": "txt",
+				"body": {"msg": "hi"},
+				"ext": {"a": 1, "b": "v"},
+				"routetype": "ROUTE_ONLINE",
+				"sync_device": true
+			}`)
+
+			// 选项只作用于当前请求
+			if _, e := em.SendMessage(context.Background(), target, "bot", []string{"x1"}, &TextBody{Msg: "hi"}); e != nil {
+				t.Fatal(e)
+			}
+
+			assertJSON(t, m.last(t), `{"from": "bot", "to": ["x1"], "type": "txt", "body": {"msg": "hi"}}`)
+		})
+	}
+
+	// 合并扩展字段不会修改调用方传入的 map
+	if len(ext) != 1 {
+		t.Errorf("caller ext was modified: %v", ext)
+	}
+}
+
+func TestBroadcastMessage(t *testing.T) {
+	var body []byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/messages/users/broadcast", func(w http.ResponseWriter, r *http.Request) {
+		var e error
+		if body, e = io.ReadAll(r.Body); e != nil {
+			t.Error(e)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"id": "broadcast-1"}})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	res, e := em.BroadcastMessage(context.Background(), "system", &TextBody{Msg: "维护通知"}, WithExt(map[string]interface{}{"k": "v"}))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if res.Id != "broadcast-1" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	assertJSON(t, body, `{"from": "system", "type": "txt", "body": {"msg": "维护通知"}, "ext": {"k": "v"}}`)
+}
+
+func TestBroadcastMessageQuotaExceeded(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/messages/users/broadcast", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusTooManyRequests, "resource_limited", "broadcast limit exceeded")
+	})
+
+	em := newTestEasemob(t, mux)
+
+	_, e := em.BroadcastMessage(context.Background(), "", &TextBody{Msg: "hi"})
+	if !errors.Is(e, ErrBroadcastQuotaExceeded) || !errors.Is(e, ErrTooManyRequests) {
+		t.Errorf("expected quota exceeded, got %v", e)
+	}
+
+	// 本地默认限流为 1 次/分钟, 第二次调用需要等待
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, e := em.BroadcastMessage(ctx, "", &TextBody{Msg: "hi"}); !errors.Is(e, context.DeadlineExceeded) {
+		t.Errorf("expected local limiter to wait, got %v", e)
+	}
+}
@@ -0,0 +1,338 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+	"uw/ureq"
+)
+
+// statDateLayout 统计接口使用的日期格式 (yyyyMMdd)
+const statDateLayout = "20060102"
+
+// UserStatMaxDays 用户统计单次查询的最大天数
+const UserStatMaxDays = 90
+
+// UserStatKind 用户统计类型
+type UserStatKind string
+
+const (
+	UserStatDailyActive UserStatKind = "dau"      // 日活跃用户数
+	UserStatDailyNew    UserStatKind = "register" // 日新增注册用户数
+	UserStatTotal       UserStatKind = "total"    // 累计用户数
+)
+
+type DailyCount struct {
+	Date  time.Time // 日期
+	Count int64     // 数量
+}
+
+type dailyCountResp struct {
+	Date  string `json:"date"`  // 日期, 格式为 yyyyMMdd
+	Count int64  `json:"count"` // 数量
+}
+
+// GetUserStatistics 获取用户数据统计 (日活, 日新增注册, 累计用户)
+// kind: 统计类型, startDate: 开始日期, endDate: 结束日期 (包含), 最多 UserStatMaxDays 天
+func (em *Easemob) GetUserStatistics(ctx context.Context, kind UserStatKind, startDate, endDate time.Time) ([]DailyCount, error) {
+	switch kind {
+	case UserStatDailyActive, UserStatDailyNew, UserStatTotal:
+	default:
+		return nil, fmt.Errorf("get user statistics error: invalid kind %q", kind)
+	}
+
+	if startDate.IsZero() || endDate.IsZero() || endDate.Before(startDate) {
+		return nil, errors.New("get user statistics error: invalid date range")
+	}
+
+	if endDate.Sub(startDate) >= UserStatMaxDays*24*time.Hour {
+		return nil, fmt.Errorf("get user statistics error: date range exceeds %d days", UserStatMaxDays)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL(path.Join("statistics/users", string(kind)))
+	q := url.Values{}
+	q.Set("start_date", startDate.Format(statDateLayout))
+	q.Set("end_date", endDate.Format(statDateLayout))
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get user statistics error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get user statistics", res)
+	}
+
+	resp := &RespCommon[[]*dailyCountResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get user statistics error: %w", e)
+	}
+
+	counts := make([]DailyCount, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		date, e := time.ParseInLocation(statDateLayout, v.Date, startDate.Location())
+		if e != nil {
+			return nil, fmt.Errorf("get user statistics error: %w", e)
+		}
+
+		counts = append(counts, DailyCount{
+			Date:  date,
+			Count: v.Count,
+		})
+	}
+
+	return counts, nil
+}
+
+type HourlyCount struct {
+	Hour  time.Time // 整点时间
+	Count int64     // 该小时内的数量
+}
+
+type MessageStats struct {
+	TotalCount int64            // 消息总数
+	PerHour    []HourlyCount    // 按小时统计的消息数
+	PerType    map[string]int64 // 按消息类型统计的消息数, 键为消息类型: txt, img, audio 等
+}
+
+type messageStatsResp struct {
+	TotalCount int64 `json:"totalCount"`
+	PerHour    []*struct {
+		Hour  int64 `json:"hour"` // Unix 时间戳, 单位为毫秒
+		Count int64 `json:"count"`
+	} `json:"perHour"`
+	PerType map[string]int64 `json:"perType"`
+}
+
+type UserStats struct {
+	TotalCount  int64 `json:"totalCount"`  // 累计用户数
+	ActiveCount int64 `json:"activeCount"` // 今日活跃用户数
+	NewCount    int64 `json:"newCount"`    // 今日新增用户数
+}
+
+// GetMessageStats 获取指定时间范围内的消息统计
+// start: 开始时间, end: 结束时间
+func (em *Easemob) GetMessageStats(ctx context.Context, start, end time.Time) (*MessageStats, error) {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return nil, errors.New("get message stats error: invalid time range")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL("statistics/messages/users")
+	q := url.Values{}
+	q.Set("start", strconv.FormatInt(start.UnixMilli(), 10))
+	q.Set("end", strconv.FormatInt(end.UnixMilli(), 10))
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get message stats error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get message stats", res)
+	}
+
+	resp := &RespCommon[*messageStatsResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get message stats error: %w", e)
+	}
+
+	stats := &MessageStats{
+		PerHour: []HourlyCount{},
+		PerType: map[string]int64{},
+	}
+
+	if resp.Data == nil {
+		return stats, nil
+	}
+
+	stats.TotalCount = resp.Data.TotalCount
+	for _, v := range resp.Data.PerHour {
+		stats.PerHour = append(stats.PerHour, HourlyCount{
+			Hour:  time.UnixMilli(v.Hour),
+			Count: v.Count,
+		})
+	}
+
+	for k, v := range resp.Data.PerType {
+		stats.PerType[k] = v
+	}
+
+	return stats, nil
+}
+
+// GetUserStats 获取当前 App 的用户统计
+func (em *Easemob) GetUserStats(ctx context.Context) (*UserStats, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL("statistics/users").String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get user stats error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get user stats", res)
+	}
+
+	resp := &RespCommon[*UserStats]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get user stats error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return &UserStats{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+type onlineCountResp struct {
+	Count int64 `json:"count"` // 在线用户数
+}
+
+// GetOnlineUserCount 获取当前 App 的在线用户数
+func (em *Easemob) GetOnlineUserCount(ctx context.Context) (int64, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return 0, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL("users/online/count").String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return 0, fmt.Errorf("get online user count error: %w", e)
+	}
+
+	if !res.OK() {
+		return 0, newResponseError("get online user count", res)
+	}
+
+	resp := &RespCommon[*onlineCountResp]{}
+	if e = res.JSON(resp); e != nil {
+		return 0, fmt.Errorf("get online user count error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return 0, nil
+	}
+
+	return resp.Data.Count, nil
+}
+
+type SystemStatus struct {
+	OnlineUserCount int64            // 在线用户数
+	RateLimit       *RateLimitStatus // 本地限流器状态
+}
+
+// GetSystemStatus 获取在线用户数和本地限流器状态, 用于运维监控
+func (em *Easemob) GetSystemStatus(ctx context.Context) (*SystemStatus, error) {
+	count, e := em.GetOnlineUserCount(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get system status error: %w", e)
+	}
+
+	return &SystemStatus{
+		OnlineUserCount: count,
+		RateLimit:       em.GetRateLimitStatus(),
+	}, nil
+}
+
+// PushStatGranularity 推送统计的时间粒度
+type PushStatGranularity string
+
+const (
+	PushStatDaily  PushStatGranularity = "DAY"  // 按天统计
+	PushStatHourly PushStatGranularity = "HOUR" // 按小时统计
+)
+
+type PushStatPoint struct {
+	Timestamp time.Time // 统计时间点
+	Sent      int64     // 发送数
+	Delivered int64     // 送达数
+	Opened    int64     // 点击数
+	Failed    int64     // 失败数
+}
+
+type PushStats struct {
+	Points []PushStatPoint // 按时间顺序排列的统计数据
+}
+
+type pushStatPointResp struct {
+	Timestamp int64 `json:"timestamp"` // Unix 时间戳, 单位为毫秒
+	Sent      int64 `json:"sent"`
+	Delivered int64 `json:"delivered"`
+	Opened    int64 `json:"opened"`
+	Failed    int64 `json:"failed"`
+}
+
+// GetPushStatistics 获取推送的发送, 送达, 点击和失败统计, 用于推送漏斗分析
+// start: 开始时间, end: 结束时间, granularity: 统计粒度
+func (em *Easemob) GetPushStatistics(ctx context.Context, start, end time.Time, granularity PushStatGranularity) (*PushStats, error) {
+	switch granularity {
+	case PushStatDaily, PushStatHourly:
+	default:
+		return nil, fmt.Errorf("get push statistics error: invalid granularity %q", granularity)
+	}
+
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return nil, errors.New("get push statistics error: invalid time range")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL("push/statistics")
+	q := url.Values{}
+	q.Set("start", strconv.FormatInt(start.UnixMilli(), 10))
+	q.Set("end", strconv.FormatInt(end.UnixMilli(), 10))
+	q.Set("granularity", string(granularity))
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get push statistics error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get push statistics", res)
+	}
+
+	resp := &RespCommon[[]*pushStatPointResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get push statistics error: %w", e)
+	}
+
+	stats := &PushStats{Points: make([]PushStatPoint, 0, len(resp.Data))}
+	for _, v := range resp.Data {
+		stats.Points = append(stats.Points, PushStatPoint{
+			Timestamp: time.UnixMilli(v.Timestamp),
+			Sent:      v.Sent,
+			Delivered: v.Delivered,
+			Opened:    v.Opened,
+			Failed:    v.Failed,
+		})
+	}
+
+	return stats, nil
+}
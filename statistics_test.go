@@ -0,0 +1,135 @@
+package easemob
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetUserStatisticsSevenDays(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 6)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/statistics/users/{kind}", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("start_date") != "20240301" || q.Get("end_date") != "20240307" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		// 不同类型使用不同的基数, 用于确认请求的是对应的接口
+		base := map[string]int64{"dau": 100, "register": 10, "total": 1000}[r.PathValue("kind")]
+		if base == 0 {
+			t.Errorf("unexpected kind: %s", r.PathValue("kind"))
+		}
+
+		data := []map[string]interface{}{}
+		for i := 0; i < 7; i++ {
+			data = append(data, map[string]interface{}{
+				"date":  start.AddDate(0, 0, i).Format("20060102"),
+				"count": base + int64(i),
+			})
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	for kind, base := range map[UserStatKind]int64{
+		UserStatDailyActive: 100,
+		UserStatDailyNew:    10,
+		UserStatTotal:       1000,
+	} {
+		counts, e := em.GetUserStatistics(context.Background(), kind, start, end)
+		if e != nil {
+			t.Fatalf("%s: %s", kind, e)
+		}
+
+		if len(counts) != 7 {
+			t.Fatalf("%s: unexpected count length %d", kind, len(counts))
+		}
+
+		for i, v := range counts {
+			if !v.Date.Equal(start.AddDate(0, 0, i)) || v.Count != base+int64(i) {
+				t.Errorf("%s: unexpected count %d: %+v", kind, i, v)
+			}
+		}
+	}
+}
+
+func TestGetUserStatisticsValidation(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name       string
+		kind       UserStatKind
+		start, end time.Time
+	}{
+		{"invalid kind", "weekly", start, start},
+		{"reversed range", UserStatDailyActive, start, start.AddDate(0, 0, -1)},
+		{"zero start", UserStatDailyActive, time.Time{}, start},
+		{"too long", UserStatDailyActive, start, start.AddDate(0, 0, UserStatMaxDays)},
+	}
+
+	for _, c := range cases {
+		if _, e := em.GetUserStatistics(context.Background(), c.kind, c.start, c.end); e == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
+
+func TestStatisticsTimeRangeQuery(t *testing.T) {
+	start, end := time.UnixMilli(1710000000000), time.UnixMilli(1710086400000)
+	var queries []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/statistics/messages/users", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"totalCount": 3,
+				"perHour":    []map[string]int64{{"hour": 1710000000000, "count": 3}},
+				"perType":    map[string]int64{"txt": 2, "img": 1},
+			},
+		})
+	})
+	mux.HandleFunc("GET /org/app/push/statistics", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]int64{{"timestamp": 1710000000000, "sent": 10, "delivered": 8, "opened": 2, "failed": 2}},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	messages, e := em.GetMessageStats(ctx, start, end)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if messages.TotalCount != 3 || len(messages.PerHour) != 1 || messages.PerType["img"] != 1 {
+		t.Errorf("unexpected message stats: %+v", messages)
+	}
+
+	push, e := em.GetPushStatistics(ctx, start, end, PushStatHourly)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if len(push.Points) != 1 || push.Points[0].Delivered != 8 || !push.Points[0].Timestamp.Equal(start) {
+		t.Errorf("unexpected push stats: %+v", push)
+	}
+
+	want := []string{
+		"end=1710086400000&start=1710000000000",
+		"end=1710086400000&granularity=HOUR&start=1710000000000",
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("unexpected queries: %q", queries)
+	}
+}
@@ -0,0 +1,233 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"uw/ureq"
+)
+
+// languageTagPattern BCP-47 语言标签的简单校验, 例如 en, zh-Hans, pt-BR
+var languageTagPattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// TranslationLanguages 支持的翻译目标语言, 用于 Translate 和 WithTranslation 的校验
+// 服务端新增支持的语言时, 可以直接向该 map 中添加语言代码 (需要在并发使用前完成)
+var TranslationLanguages = map[string]bool{
+	"ar": true, "de": true, "en": true, "es": true, "fr": true, "hi": true,
+	"id": true, "it": true, "ja": true, "ko": true, "ms": true, "pt": true,
+	"ru": true, "th": true, "tr": true, "vi": true, "zh-Hans": true, "zh-Hant": true,
+}
+
+// validateTranslationLanguages 校验翻译目标语言, 必须非空且都在 TranslationLanguages 中
+func validateTranslationLanguages(langs []string) error {
+	if len(langs) < 1 {
+		return errors.New("target languages is empty")
+	}
+
+	for _, v := range langs {
+		if !TranslationLanguages[v] {
+			return fmt.Errorf("unsupported language %q", v)
+		}
+	}
+
+	return nil
+}
+
+type TranslateResult struct {
+	TranslatedText   string  `json:"translatedText"`   // 译文
+	DetectedLanguage string  `json:"detectedLanguage"` // 检测到的源语言
+	Confidence       float64 `json:"confidence"`       // 源语言检测的置信度, 范围 [0, 1]
+}
+
+type translateReq struct {
+	Text string `json:"text"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+}
+
+// TranslateMessage 使用 Easemob 托管的翻译服务翻译文本, 消耗 Easemob 的翻译额度
+// text: 待翻译文本, from: 源语言, 为空时自动检测, to: 目标语言 (BCP-47 语言标签)
+func (em *Easemob) TranslateMessage(ctx context.Context, text, from, to string) (*TranslateResult, error) {
+	if len(text) < 1 {
+		return nil, errors.New("translate message error: text is empty")
+	}
+
+	if len(from) > 0 && !languageTagPattern.MatchString(from) {
+		return nil, fmt.Errorf("translate message error: invalid language tag %q", from)
+	}
+
+	if !languageTagPattern.MatchString(to) {
+		return nil, fmt.Errorf("translate message error: invalid language tag %q", to)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("messages/translate").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&translateReq{
+			Text: text,
+			From: from,
+			To:   to,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("translate message error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("translate message", res)
+	}
+
+	resp := &RespCommon[*TranslateResult]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("translate message error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return nil, errors.New("translate message error: empty data")
+	}
+
+	return resp.Data, nil
+}
+
+// TranslateBatchMaxItems 批量翻译单次请求的最大条数, 超过时 BatchTranslateMessages 会自动分批
+const TranslateBatchMaxItems = 50
+
+type TranslateItem struct {
+	MsgId string `json:"msgId"` // 消息 ID, 用于对应翻译结果
+	Text  string `json:"text"`  // 待翻译文本
+}
+
+type batchTranslateReq struct {
+	Items []TranslateItem `json:"items"`
+	To    string          `json:"to"`
+}
+
+type batchTranslateResp struct {
+	MsgId string `json:"msgId"`
+	TranslateResult
+}
+
+// BatchTranslateMessages 批量翻译消息, 返回结果与 items 顺序一致
+// 每 TranslateBatchMaxItems 条为一批请求, 某条消息没有返回翻译结果时对应位置为 nil
+// items: 待翻译消息, targetLang: 目标语言 (BCP-47 语言标签)
+func (em *Easemob) BatchTranslateMessages(ctx context.Context, items []TranslateItem, targetLang string) ([]*TranslateResult, error) {
+	if len(items) < 1 {
+		return nil, errors.New("batch translate messages error: items is empty")
+	}
+
+	if !languageTagPattern.MatchString(targetLang) {
+		return nil, fmt.Errorf("batch translate messages error: invalid language tag %q", targetLang)
+	}
+
+	results := make([]*TranslateResult, 0, len(items))
+	for i := 0; i < len(items); i += TranslateBatchMaxItems {
+		batch, e := em.batchTranslateMessages(ctx, items[i:min(i+TranslateBatchMaxItems, len(items))], targetLang)
+		if e != nil {
+			return nil, e
+		}
+
+		results = append(results, batch...)
+	}
+
+	return results, nil
+}
+
+func (em *Easemob) batchTranslateMessages(ctx context.Context, items []TranslateItem, targetLang string) ([]*TranslateResult, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("messages/translate/batch").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&batchTranslateReq{
+			Items: items,
+			To:    targetLang,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("batch translate messages error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("batch translate messages", res)
+	}
+
+	resp := &RespCommon[[]*batchTranslateResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("batch translate messages error: %w", e)
+	}
+
+	byMsgId := make(map[string]*TranslateResult, len(resp.Data))
+	for _, v := range resp.Data {
+		result := v.TranslateResult
+		byMsgId[v.MsgId] = &result
+	}
+
+	results := make([]*TranslateResult, len(items))
+	for i, v := range items {
+		results[i] = byMsgId[v.MsgId]
+	}
+
+	return results, nil
+}
+
+type multiTranslateReq struct {
+	Text string   `json:"text"`
+	To   []string `json:"to"`
+}
+
+type multiTranslateResp struct {
+	Language string `json:"language"`
+	Text     string `json:"text"`
+}
+
+// Translate 将文本翻译为多种语言, 用于发送前预览翻译结果
+// 返回的 map 以目标语言为 key, 译文为 value
+// text: 待翻译文本, targetLangs: 目标语言, 必须在 TranslationLanguages 中
+func (em *Easemob) Translate(ctx context.Context, text string, targetLangs []string) (map[string]string, error) {
+	if len(text) < 1 {
+		return nil, errors.New("translate error: text is empty")
+	}
+
+	if e := validateTranslationLanguages(targetLangs); e != nil {
+		return nil, fmt.Errorf("translate error: %w", e)
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("messages/translations").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&multiTranslateReq{
+			Text: text,
+			To:   targetLangs,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("translate error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("translate", res)
+	}
+
+	resp := &RespCommon[[]*multiTranslateResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("translate error: %w", e)
+	}
+
+	translations := make(map[string]string, len(resp.Data))
+	for _, v := range resp.Data {
+		translations[v.Language] = v.Text
+	}
+
+	return translations, nil
+}
@@ -0,0 +1,85 @@
+package easemob
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSendMessageWithTranslation(t *testing.T) {
+	rec := &messageRecorder{}
+	em := newTestEasemob(t, rec.handler(t))
+	ctx := context.Background()
+
+	if _, e := em.SendTextMessage(ctx, "alice", []string{"bob"}, "你好", WithTranslation("en", "ja")); e != nil {
+		t.Fatal(e)
+	}
+
+	req := map[string]interface{}{}
+	if e := json.Unmarshal(rec.last(t), &req); e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(req["translations"], []interface{}{"en", "ja"}) {
+		t.Errorf("unexpected translations: %v", req["translations"])
+	}
+
+	// 不支持的语言和非文本消息不会发出请求
+	if _, e := em.SendTextMessage(ctx, "alice", []string{"bob"}, "你好", WithTranslation("xx")); e == nil {
+		t.Error("expected error for unsupported language")
+	}
+
+	if _, e := em.SendCmdMessage(ctx, "alice", []string{"bob"}, "refresh", WithTranslation("en")); e == nil {
+		t.Error("expected error for translation of cmd message")
+	}
+
+	if len(rec.bodies) != 1 {
+		t.Errorf("unexpected request count: %d", len(rec.bodies))
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/messages/translations", func(w http.ResponseWriter, r *http.Request) {
+		req := &multiTranslateReq{}
+		decodeBody(t, r, req)
+
+		if req.Text != "你好" || !reflect.DeepEqual(req.To, []string{"en", "ja", "fr"}) {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]string{
+				{"language": "en", "text": "Hello"},
+				{"language": "ja", "text": "こんにちは"},
+				{"language": "fr", "text": "Bonjour"},
+			},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	got, e := em.Translate(context.Background(), "你好", []string{"en", "ja", "fr"})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := map[string]string{"en": "Hello", "ja": "こんにちは", "fr": "Bonjour"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected translations: %v", got)
+	}
+}
+
+func TestTranslateLanguages(t *testing.T) {
+	em := newTestEasemob(t, failHandler(t))
+
+	if _, e := em.Translate(context.Background(), "你好", nil); e == nil {
+		t.Error("expected error for empty languages")
+	}
+
+	if _, e := em.Translate(context.Background(), "你好", []string{"en", "tlh"}); e == nil {
+		t.Error("expected error for unsupported language")
+	}
+}
@@ -0,0 +1,271 @@
+package easemob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"uw/ureq"
+)
+
+type uploadFileResp struct {
+	Entities []*struct {
+		Uuid        string `json:"uuid"`         // 文件的 UUID
+		ShareSecret string `json:"share-secret"` // 文件的访问密钥
+	} `json:"entities"`
+}
+
+// ChatFileDefaultMaxSize 上传文件的默认大小上限, 可以通过 SetChatFileMaxSize 修改
+const ChatFileDefaultMaxSize = 10 << 20
+
+// ErrChatFileTooLarge 上传文件超过大小上限
+var ErrChatFileTooLarge = errors.New("chat file too large")
+
+type ChatFile struct {
+	UUID        string // 文件的 UUID
+	ShareSecret string // 文件的访问密钥, 下载受限文件时需要
+	URL         string // 文件的下载地址
+}
+
+// UploadChatFile 上传文件到 chatfiles, 用于发送图片, 语音, 视频和文件消息
+// 文件以流的方式上传, 不会在内存中缓存整个文件, 超过大小上限时返回的错误满足 errors.Is(e, ErrChatFileTooLarge)
+// r: 文件内容, filename: 文件名, restrictAccess: 是否限制访问, 为 true 时下载需要 ShareSecret
+func (em *Easemob) UploadChatFile(ctx context.Context, r io.Reader, filename string, restrictAccess bool) (*ChatFile, error) {
+	if r == nil || len(filename) < 1 {
+		return nil, errors.New("upload chat file error: reader or filename is empty")
+	}
+
+	return em.uploadFile(ctx, "upload chat file", r, filename, restrictAccess)
+}
+
+// uploadFile 以 multipart/form-data 流式上传文件到 chatfiles
+func (em *Easemob) uploadFile(ctx context.Context, op string, r io.Reader, filename string, restrictAccess bool) (*ChatFile, error) {
+	em.mu.RLock()
+	maxSize := em.chatFileMaxSize
+	em.mu.RUnlock()
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		part, e := mw.CreateFormFile("file", filename)
+		if e == nil {
+			var n int64
+			n, e = io.Copy(part, io.LimitReader(r, maxSize+1))
+			if e == nil && n > maxSize {
+				e = fmt.Errorf("%w: exceeds %d bytes", ErrChatFileTooLarge, maxSize)
+			}
+		}
+		if e == nil {
+			e = mw.Close()
+		}
+
+		_ = pw.CloseWithError(e)
+	}()
+
+	if restrictAccess {
+		c = c.Set("restrict-access", "true")
+	}
+
+	// Send 会把 Content-Type 设置为 application/json, multipart 的 Content-Type 需要在 Send 之后设置
+	res, e := em.end(c.Post(em.GetURL("chatfiles").String()).
+		Set(ureq.Accept, "application/json").
+		Send(pr).
+		Set(ureq.ContentType, mw.FormDataContentType()))
+	_ = pr.Close()
+	if e != nil {
+		return nil, fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError(op, res)
+	}
+
+	resp := &uploadFileResp{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("%s error: %w", op, e)
+	}
+
+	if len(resp.Entities) < 1 {
+		return nil, fmt.Errorf("%s error: empty entities", op)
+	}
+
+	return &ChatFile{
+		UUID:        resp.Entities[0].Uuid,
+		ShareSecret: resp.Entities[0].ShareSecret,
+		URL:         em.GetURL(path.Join("chatfiles", resp.Entities[0].Uuid)).String(),
+	}, nil
+}
+
+// sniffImage 检查图片类型, 只允许 JPEG 和 PNG, 返回可以重新完整读取的 Reader
+func sniffImage(r io.Reader) (io.Reader, error) {
+	head := make([]byte, 512)
+	n, e := io.ReadFull(r, head)
+	if e != nil && !errors.Is(e, io.ErrUnexpectedEOF) && !errors.Is(e, io.EOF) {
+		return nil, e
+	}
+
+	head = head[:n]
+	switch http.DetectContentType(head) {
+	case "image/jpeg", "image/png":
+	default:
+		return nil, errors.New("only jpeg and png images are allowed")
+	}
+
+	return io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+type groupAvatarReq struct {
+	Avatar string `json:"avatar"`
+}
+
+// UploadGroupAvatar 上传图片并设置为群组头像, 只允许 JPEG 和 PNG
+// groupId: 群组 ID, r: 图片内容, filename: 文件名
+func (em *Easemob) UploadGroupAvatar(ctx context.Context, groupId string, r io.Reader, filename string) error {
+	if len(groupId) < 1 || len(filename) < 1 {
+		return errors.New("upload group avatar error: group id or filename is empty")
+	}
+
+	r, e := sniffImage(r)
+	if e != nil {
+		return fmt.Errorf("upload group avatar error: %w", e)
+	}
+
+	file, e := em.uploadFile(ctx, "upload group avatar", r, filename, false)
+	if e != nil {
+		return e
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Put(em.GetURL(path.Join("chatgroups", groupId)).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&groupAvatarReq{
+			Avatar: file.URL,
+		}))
+	if e != nil {
+		return fmt.Errorf("upload group avatar error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("upload group avatar", res)
+	}
+
+	return nil
+}
+
+// UploadUserAvatar 上传图片并设置为用户头像 (用户属性 avatarurl), 只允许 JPEG 和 PNG
+// username: 用户 ID, r: 图片内容, filename: 文件名
+func (em *Easemob) UploadUserAvatar(ctx context.Context, username string, r io.Reader, filename string) error {
+	if len(username) < 1 || len(filename) < 1 {
+		return errors.New("upload user avatar error: username or filename is empty")
+	}
+
+	r, e := sniffImage(r)
+	if e != nil {
+		return fmt.Errorf("upload user avatar error: %w", e)
+	}
+
+	file, e := em.uploadFile(ctx, "upload user avatar", r, filename, false)
+	if e != nil {
+		return e
+	}
+
+	if e := em.UpdateUserProfile(ctx, username, UserProfileUpdate{AvatarUrl: &file.URL}); e != nil {
+		return fmt.Errorf("upload user avatar error: %w", e)
+	}
+
+	return nil
+}
+
+// ErrChatFileAccessDenied 下载文件时访问密钥无效或已过期
+var ErrChatFileAccessDenied = errors.New("chat file access denied")
+
+// DownloadChatFile 流式下载 chatfiles 中的文件并写入 w, 返回写入的字节数
+// 访问密钥无效或已过期时返回的错误满足 errors.Is(e, ErrChatFileAccessDenied)
+// uuid: 文件的 UUID, shareSecret: 文件的访问密钥, 未限制访问的文件可为空, w: 文件内容的写入目标
+func (em *Easemob) DownloadChatFile(ctx context.Context, uuid, shareSecret string, w io.Writer) (int64, error) {
+	if len(uuid) < 1 {
+		return 0, errors.New("download chat file error: uuid is empty")
+	}
+
+	return em.downloadChatFile(ctx, "download chat file", uuid, shareSecret, false, w)
+}
+
+// DownloadChatFileOf 下载 UploadChatFile 上传的文件, 参见 DownloadChatFile
+// file: UploadChatFile 的返回值, w: 文件内容的写入目标
+func (em *Easemob) DownloadChatFileOf(ctx context.Context, file *ChatFile, w io.Writer) (int64, error) {
+	if file == nil {
+		return 0, errors.New("download chat file error: file is nil")
+	}
+
+	return em.DownloadChatFile(ctx, file.UUID, file.ShareSecret, w)
+}
+
+func (em *Easemob) downloadChatFile(ctx context.Context, op, uuid, shareSecret string, thumbnail bool, w io.Writer) (int64, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return 0, fmt.Errorf("get client error: %w", e)
+	}
+
+	if len(shareSecret) > 0 {
+		c = c.Set("share-secret", shareSecret)
+	}
+
+	if thumbnail {
+		c = c.Set("thumbnail", "true")
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("chatfiles", uuid)).String()).
+		Set(ureq.Accept, "application/octet-stream"))
+	if e != nil {
+		return 0, fmt.Errorf("%s error: %w", op, e)
+	}
+	defer res.Body.Close()
+
+	if !res.OK() {
+		e := newResponseError(op, res)
+		if errors.Is(e, ErrUnauthorized) || errors.Is(e, ErrForbidden) {
+			return 0, fmt.Errorf("%w: %w", ErrChatFileAccessDenied, e)
+		}
+
+		return 0, e
+	}
+
+	n, e := io.Copy(w, res.Body)
+	if e != nil {
+		return n, fmt.Errorf("%s error: %w", op, e)
+	}
+
+	return n, nil
+}
+
+// DownloadThumbnail 流式下载图片或视频的缩略图并写入 w, 返回写入的字节数
+// 服务端没有缩略图时改为下载原文件, 此时 original 为 true
+// uuid: 文件的 UUID, shareSecret: 文件的访问密钥, 未限制访问的文件可为空, w: 缩略图内容的写入目标
+func (em *Easemob) DownloadThumbnail(ctx context.Context, uuid, shareSecret string, w io.Writer) (n int64, original bool, e error) {
+	if len(uuid) < 1 {
+		return 0, false, errors.New("download thumbnail error: uuid is empty")
+	}
+
+	n, e = em.downloadChatFile(ctx, "download thumbnail", uuid, shareSecret, true, w)
+	if !errors.Is(e, ErrNotFound) {
+		return n, false, e
+	}
+
+	n, e = em.downloadChatFile(ctx, "download thumbnail", uuid, shareSecret, false, w)
+	return n, true, e
+}
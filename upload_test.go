@@ -0,0 +1,248 @@
+package easemob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// chatFileServer 解析 multipart 上传请求, 保存上传的文件
+type chatFileServer struct {
+	restricted []bool
+	filenames  []string
+	contents   []string
+}
+
+func (s *chatFileServer) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /org/app/chatfiles", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			t.Errorf("unexpected content type: %s", r.Header.Get("Content-Type"))
+		}
+
+		file, header, e := r.FormFile("file")
+		if e != nil {
+			writeError(w, http.StatusBadRequest, "illegal_argument", e.Error())
+			return
+		}
+		defer file.Close()
+
+		data, e := io.ReadAll(file)
+		if e != nil {
+			t.Error(e)
+		}
+
+		s.restricted = append(s.restricted, r.Header.Get("restrict-access") == "true")
+		s.filenames = append(s.filenames, header.Filename)
+		s.contents = append(s.contents, string(data))
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"entities": []map[string]string{{"uuid": "file-uuid", "share-secret": "file-secret", "type": "chatfile"}},
+		})
+	})
+
+	return mux
+}
+
+func TestUploadChatFile(t *testing.T) {
+	s := &chatFileServer{}
+	em := newTestEasemob(t, s.handler(t))
+	ctx := context.Background()
+
+	file, e := em.UploadChatFile(ctx, strings.NewReader("public content"), "public.txt", false)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if file.UUID != "file-uuid" || file.ShareSecret != "file-secret" ||
+		!strings.HasSuffix(file.URL, "/org/app/chatfiles/file-uuid") {
+		t.Errorf("unexpected file: %+v", file)
+	}
+
+	if _, e := em.UploadChatFile(ctx, strings.NewReader("private content"), "private.txt", true); e != nil {
+		t.Fatal(e)
+	}
+
+	if len(s.filenames) != 2 {
+		t.Fatalf("unexpected upload count: %d", len(s.filenames))
+	}
+
+	if s.restricted[0] || !s.restricted[1] {
+		t.Errorf("unexpected restrict-access headers: %v", s.restricted)
+	}
+
+	if s.filenames[0] != "public.txt" || s.contents[0] != "public content" ||
+		s.filenames[1] != "private.txt" || s.contents[1] != "private content" {
+		t.Errorf("unexpected uploads: %v, %v", s.filenames, s.contents)
+	}
+}
+
+func TestUploadChatFileTooLarge(t *testing.T) {
+	s := &chatFileServer{}
+	em := newTestEasemob(t, s.handler(t))
+	em.SetChatFileMaxSize(16)
+
+	_, e := em.UploadChatFile(context.Background(), bytes.NewReader(make([]byte, 17)), "large.bin", false)
+	if !errors.Is(e, ErrChatFileTooLarge) {
+		t.Errorf("expected too large error, got %v", e)
+	}
+
+	if len(s.contents) != 0 {
+		t.Errorf("oversized file should not be stored: %d bytes", len(s.contents[0]))
+	}
+
+	if _, e := em.UploadChatFile(context.Background(), bytes.NewReader(make([]byte, 16)), "small.bin", false); e != nil {
+		t.Fatal(e)
+	}
+}
+
+// limitedWriter 最多接受 n 个字节, 超过时返回错误
+type limitedWriter struct {
+	buf bytes.Buffer
+	n   int
+}
+
+var errWriterFull = errors.New("writer is full")
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.n {
+		k := w.n - w.buf.Len()
+		w.buf.Write(p[:k])
+		return k, errWriterFull
+	}
+
+	return w.buf.Write(p)
+}
+
+func TestDownloadChatFile(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatfiles/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/octet-stream" || r.Header.Get("thumbnail") != "" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+
+		switch secret := r.Header.Get("share-secret"); {
+		case r.PathValue("uuid") == "missing":
+			writeError(w, http.StatusNotFound, "file_not_found", "file not found")
+		case secret == "expired":
+			writeError(w, http.StatusUnauthorized, "unauthorized", "share secret is expired")
+		case secret != "file-secret":
+			writeError(w, http.StatusForbidden, "forbidden_op", "share secret is invalid")
+		default:
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = io.WriteString(w, content)
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	out := &bytes.Buffer{}
+	n, e := em.DownloadChatFileOf(ctx, &ChatFile{UUID: "file-uuid", ShareSecret: "file-secret"}, out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if n != int64(len(content)) || out.String() != content {
+		t.Errorf("unexpected content: %d bytes", n)
+	}
+
+	// 写入目标已满时返回已写入的字节数
+	lw := &limitedWriter{n: 4096}
+	n, e = em.DownloadChatFile(ctx, "file-uuid", "file-secret", lw)
+	if !errors.Is(e, errWriterFull) || n != 4096 || lw.buf.String() != content[:4096] {
+		t.Errorf("unexpected limited download: %d bytes, %v", n, e)
+	}
+
+	for _, secret := range []string{"expired", "wrong"} {
+		if _, e := em.DownloadChatFile(ctx, "file-uuid", secret, io.Discard); !errors.Is(e, ErrChatFileAccessDenied) {
+			t.Errorf("%s: expected access denied, got %v", secret, e)
+		}
+	}
+
+	_, e = em.DownloadChatFile(ctx, "missing", "file-secret", io.Discard)
+	if !errors.Is(e, ErrNotFound) || errors.Is(e, ErrChatFileAccessDenied) {
+		t.Errorf("expected not found, got %v", e)
+	}
+}
+
+func TestDownloadThumbnail(t *testing.T) {
+	var thumbnails, secrets []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/chatfiles/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		thumbnail := r.Header.Get("thumbnail")
+		thumbnails = append(thumbnails, thumbnail)
+		secrets = append(secrets, r.Header.Get("share-secret"))
+
+		switch {
+		case thumbnail != "true":
+			_, _ = io.WriteString(w, "original")
+		case r.PathValue("uuid") == "no-thumb":
+			writeError(w, http.StatusNotFound, "file_not_found", "thumbnail not found")
+		default:
+			_, _ = io.WriteString(w, "thumb")
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	out := &bytes.Buffer{}
+	n, original, e := em.DownloadThumbnail(ctx, "img", "", out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if original || n != 5 || out.String() != "thumb" {
+		t.Errorf("unexpected thumbnail: %q, original %v", out.String(), original)
+	}
+
+	if len(thumbnails) != 1 || thumbnails[0] != "true" {
+		t.Errorf("unexpected thumbnail headers: %v", thumbnails)
+	}
+
+	// 没有缩略图时改为下载原文件
+	out.Reset()
+	thumbnails = nil
+
+	secrets = nil
+
+	n, original, e = em.DownloadThumbnail(ctx, "no-thumb", "secret-1", out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !original || n != 8 || out.String() != "original" {
+		t.Errorf("unexpected fallback: %q, original %v", out.String(), original)
+	}
+
+	// 回退请求使用新的客户端, 不带缩略图请求头, 但仍然带访问密钥
+	if !reflect.DeepEqual(thumbnails, []string{"true", ""}) || !reflect.DeepEqual(secrets, []string{"secret-1", "secret-1"}) {
+		t.Errorf("unexpected headers: thumbnail %v, share-secret %v", thumbnails, secrets)
+	}
+
+	// 缩略图请求头不会影响之后的下载
+	out.Reset()
+	thumbnails = nil
+	secrets = nil
+
+	if _, _, e := em.DownloadThumbnail(ctx, "img", "secret-2", out); e != nil {
+		t.Fatal(e)
+	}
+
+	if _, e := em.DownloadChatFile(ctx, "img", "", out); e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(thumbnails, []string{"true", ""}) || !reflect.DeepEqual(secrets, []string{"secret-2", ""}) {
+		t.Errorf("unexpected headers: thumbnail %v, share-secret %v", thumbnails, secrets)
+	}
+}
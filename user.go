@@ -0,0 +1,711 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+	"uw/ureq"
+)
+
+type JoinedGroup struct {
+	GroupId   string `json:"groupid"`   // 群组 ID
+	GroupName string `json:"groupname"` // 群组名称
+}
+
+type JoinedGroupsPage struct {
+	Groups []*JoinedGroup // 当前页的群组列表
+	Count  int            // 用户加入的群组总数, 由服务端返回
+}
+
+type JoinedRoom struct {
+	Id   string `json:"id"`   // 聊天室 ID
+	Name string `json:"name"` // 聊天室名称
+}
+
+// GetUserJoinedGroups 分页获取用户加入的群组
+// username: 用户 ID, pageNum: 页码 (从 1 开始), pageSize: 每页数量
+func (em *Easemob) GetUserJoinedGroups(ctx context.Context, username string, pageNum, pageSize int) (*JoinedGroupsPage, error) {
+	if len(username) < 1 {
+		return nil, errors.New("get user joined groups error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL(path.Join("users", username, "joined_chatgroups"))
+	q := url.Values{}
+	if pageNum > 0 {
+		q.Set("pagenum", strconv.Itoa(pageNum))
+	}
+	if pageSize > 0 {
+		q.Set("pagesize", strconv.Itoa(pageSize))
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get user joined groups error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get user joined groups", res)
+	}
+
+	resp := &RespCommon[[]*JoinedGroup]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get user joined groups error: %w", e)
+	}
+
+	return &JoinedGroupsPage{
+		Groups: resp.Data,
+		Count:  resp.Count,
+	}, nil
+}
+
+// GetUserJoinedChatrooms 获取用户加入的聊天室
+// username: 用户 ID
+func (em *Easemob) GetUserJoinedChatrooms(ctx context.Context, username string) ([]JoinedRoom, error) {
+	if len(username) < 1 {
+		return nil, errors.New("get user joined chatrooms error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("users", username, "joined_chatrooms")).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get user joined chatrooms error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get user joined chatrooms", res)
+	}
+
+	resp := &RespCommon[[]JoinedRoom]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get user joined chatrooms error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return []JoinedRoom{}, nil
+	}
+
+	return resp.Data, nil
+}
+
+type UserProfileUpdate struct {
+	Nickname     *string // 用户属性中的昵称, nil 表示不修改
+	AvatarUrl    *string // 用户属性中的头像 URL, nil 表示不修改
+	PushNickname *string // 推送昵称 (用户实体), nil 表示不修改
+}
+
+type pushNicknameReq struct {
+	Nickname string `json:"nickname"`
+}
+
+// UpdateUserProfile 同时更新用户属性 (昵称, 头像) 和推送昵称
+// 先更新用户属性再更新推送昵称, 未设置的字段对应的请求会被跳过,
+// 返回的错误会指明是哪一步失败 (metadata 或 push nickname)
+// username: 用户 ID, profile: 需要更新的字段
+func (em *Easemob) UpdateUserProfile(ctx context.Context, username string, profile UserProfileUpdate) error {
+	if len(username) < 1 {
+		return errors.New("update user profile error: username is empty")
+	}
+
+	metadata := url.Values{}
+	if profile.Nickname != nil {
+		metadata.Set("nickname", *profile.Nickname)
+	}
+	if profile.AvatarUrl != nil {
+		metadata.Set("avatarurl", *profile.AvatarUrl)
+	}
+
+	if len(metadata) > 0 {
+		c, e := em.GetAccessClient(ctx)
+		if e != nil {
+			return fmt.Errorf("get client error: %w", e)
+		}
+
+		// Send 会把 Content-Type 设置为 application/json, 表单的 Content-Type 需要在 Send 之后设置
+		res, e := em.end(c.Put(em.GetURL(path.Join("metadata/user", username)).String()).
+			Set(ureq.Accept, "application/json").
+			Send(metadata.Encode()).
+			Set(ureq.ContentType, "application/x-www-form-urlencoded"))
+		if e != nil {
+			return fmt.Errorf("update user profile error: metadata: %w", e)
+		}
+
+		if !res.OK() {
+			return newResponseError("update user profile metadata", res)
+		}
+	}
+
+	if profile.PushNickname != nil {
+		c, e := em.GetAccessClient(ctx)
+		if e != nil {
+			return fmt.Errorf("get client error: %w", e)
+		}
+
+		res, e := em.end(c.Put(em.GetURL(path.Join("users", username)).String()).
+			Set(ureq.ContentType, "application/json").
+			Set(ureq.Accept, "application/json").
+			Send(&pushNicknameReq{
+				Nickname: *profile.PushNickname,
+			}))
+		if e != nil {
+			return fmt.Errorf("update user profile error: push nickname: %w", e)
+		}
+
+		if !res.OK() {
+			return newResponseError("update user profile push nickname", res)
+		}
+	}
+
+	return nil
+}
+
+// ImportUsersMaxBatch 单次导入用户的最大数量, 超过时 ImportUsers 会自动分批
+const ImportUsersMaxBatch = 60
+
+type ImportUserReq struct {
+	Username string            `json:"username"`           // 用户 ID
+	Password string            `json:"password"`           // 用户密码
+	Nickname string            `json:"nickname,omitempty"` // 推送昵称, 可选
+	Ext      map[string]string `json:"ext,omitempty"`      // 扩展信息, 可选
+}
+
+type ImportUserStatus struct {
+	Username string `json:"username"`         // 用户 ID
+	Success  bool   `json:"success"`          // 是否导入成功
+	Reason   string `json:"reason,omitempty"` // 失败原因
+}
+
+type ImportResult struct {
+	Users []*ImportUserStatus // 每个用户的导入结果
+}
+
+// Failed 返回导入失败的用户结果
+func (r *ImportResult) Failed() []*ImportUserStatus {
+	failed := []*ImportUserStatus{}
+	for _, v := range r.Users {
+		if !v.Success {
+			failed = append(failed, v)
+		}
+	}
+
+	return failed
+}
+
+// ImportUsers 批量导入用户, 用于从其他系统迁移数据 (与注册用户接口不同)
+// 接口单次最多导入 ImportUsersMaxBatch 个用户, 超过时自动分批依次导入,
+// 某一批请求失败时返回错误以及此前已完成批次的结果
+func (em *Easemob) ImportUsers(ctx context.Context, users []*ImportUserReq) (*ImportResult, error) {
+	if len(users) < 1 {
+		return nil, errors.New("import users error: users is empty")
+	}
+
+	for _, v := range users {
+		if v == nil || len(v.Username) < 1 || len(v.Password) < 1 {
+			return nil, errors.New("import users error: username or password is empty")
+		}
+	}
+
+	result := &ImportResult{Users: []*ImportUserStatus{}}
+	for i := 0; i < len(users); i += ImportUsersMaxBatch {
+		statuses, e := em.importUsers(ctx, users[i:min(i+ImportUsersMaxBatch, len(users))])
+		if e != nil {
+			return result, e
+		}
+
+		result.Users = append(result.Users, statuses...)
+	}
+
+	return result, nil
+}
+
+func (em *Easemob) importUsers(ctx context.Context, users []*ImportUserReq) ([]*ImportUserStatus, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("users/import").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(users))
+	if e != nil {
+		return nil, fmt.Errorf("import users error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("import users", res)
+	}
+
+	resp := &RespCommon[[]*ImportUserStatus]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("import users error: %w", e)
+	}
+
+	return resp.Data, nil
+}
+
+type UserMuteStatus struct {
+	Username  string `json:"userid"`    // 用户 ID
+	Chat      int64  `json:"chat"`      // 单聊剩余禁言时长, 单位为秒, 0 表示未禁言, -1 表示永久禁言
+	GroupChat int64  `json:"groupchat"` // 群聊剩余禁言时长, 单位为秒
+	Chatroom  int64  `json:"chatroom"`  // 聊天室剩余禁言时长, 单位为秒
+	UnixTime  int64  `json:"unixtime"`  // 当前服务器时间, Unix 时间戳, 单位为秒
+}
+
+// GetUserMuteStatus 查询单个用户的全局禁言状态
+// username: 用户 ID
+func (em *Easemob) GetUserMuteStatus(ctx context.Context, username string) (*UserMuteStatus, error) {
+	if len(username) < 1 {
+		return nil, errors.New("get user mute status error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("mutes", username)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get user mute status error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get user mute status", res)
+	}
+
+	resp := &RespCommon[*UserMuteStatus]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get user mute status error: %w", e)
+	}
+
+	if resp.Data == nil {
+		return nil, errors.New("get user mute status error: empty data")
+	}
+
+	return resp.Data, nil
+}
+
+// GetUsersMuteStatus 批量查询用户的全局禁言状态
+// 并发查询每个用户 (并发数见 SetBatchConcurrency), 请求仍然经过限流器,
+// 单个用户查询失败不会中断整个批次: 返回的 map 只包含查询成功的用户, 失败的用户会合并到返回的错误中;
+// ctx 取消后尚未开始查询的用户不再发出请求, 以 ctx 的错误记为失败
+// usernames: 用户 ID 列表
+func (em *Easemob) GetUsersMuteStatus(ctx context.Context, usernames []string) (map[string]*UserMuteStatus, error) {
+	em.mu.RLock()
+	concurrency := em.batchConcurrency
+	em.mu.RUnlock()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, max(concurrency, 1))
+		statuses = make(map[string]*UserMuteStatus, len(usernames))
+		errs     = []error{}
+	)
+
+	for _, username := range usernames {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("%s: %w", username, ctx.Err()))
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(username string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			status, e := em.GetUserMuteStatus(ctx, username)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if e != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", username, e))
+				return
+			}
+
+			statuses[username] = status
+		}(username)
+	}
+
+	wg.Wait()
+
+	return statuses, errors.Join(errs...)
+}
+
+type userEntitiesResp struct {
+	Entities []*UserEntity `json:"entities"` // 用户实体列表
+	Cursor   string        `json:"cursor"`   // 分页游标
+}
+
+type createUserReq struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Nickname string `json:"nickname,omitempty"`
+}
+
+// CreateUser 注册单个用户
+// username: 用户 ID, password: 用户密码, nickname: 推送昵称, 可为空
+func (em *Easemob) CreateUser(ctx context.Context, username, password, nickname string) (*UserEntity, error) {
+	if len(username) < 1 || len(password) < 1 {
+		return nil, errors.New("create user error: username or password is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("users").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&createUserReq{
+			Username: username,
+			Password: password,
+			Nickname: nickname,
+		}))
+	if e != nil {
+		return nil, fmt.Errorf("create user error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("create user", res)
+	}
+
+	resp := &userEntitiesResp{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("create user error: %w", e)
+	}
+
+	if len(resp.Entities) < 1 {
+		return nil, errors.New("create user error: empty entities")
+	}
+
+	return resp.Entities[0], nil
+}
+
+// GetUser 获取单个用户的详情
+// username: 用户 ID
+func (em *Easemob) GetUser(ctx context.Context, username string) (*UserEntity, error) {
+	if len(username) < 1 {
+		return nil, errors.New("get user error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("users", username)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get user error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get user", res)
+	}
+
+	resp := &userEntitiesResp{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get user error: %w", e)
+	}
+
+	if len(resp.Entities) < 1 {
+		return nil, errors.New("get user error: empty entities")
+	}
+
+	return resp.Entities[0], nil
+}
+
+// ListUsers 分页获取用户列表
+// limit: 每页数量, cursor: 分页游标, 首页传空; 返回当前页用户和下一页游标 (为空表示没有更多数据)
+func (em *Easemob) ListUsers(ctx context.Context, limit int, cursor string) ([]*UserEntity, string, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, "", fmt.Errorf("get client error: %w", e)
+	}
+
+	u := em.GetURL("users")
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if len(cursor) > 0 {
+		q.Set("cursor", cursor)
+	}
+
+	res, e := em.end(withQuery(c.Get(u.String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, "", fmt.Errorf("list users error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, "", newResponseError("list users", res)
+	}
+
+	resp := &userEntitiesResp{}
+	if e = res.JSON(resp); e != nil {
+		return nil, "", fmt.Errorf("list users error: %w", e)
+	}
+
+	return resp.Entities, resp.Cursor, nil
+}
+
+// DeleteUser 删除单个用户, 返回被删除的用户实体
+// username: 用户 ID
+func (em *Easemob) DeleteUser(ctx context.Context, username string) (*UserEntity, error) {
+	if len(username) < 1 {
+		return nil, errors.New("delete user error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("users", username)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("delete user error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("delete user", res)
+	}
+
+	resp := &userEntitiesResp{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("delete user error: %w", e)
+	}
+
+	if len(resp.Entities) < 1 {
+		return nil, errors.New("delete user error: empty entities")
+	}
+
+	return resp.Entities[0], nil
+}
+
+// DeactivateUser 封禁用户, 封禁后用户无法登录
+// username: 用户 ID
+func (em *Easemob) DeactivateUser(ctx context.Context, username string) error {
+	if len(username) < 1 {
+		return errors.New("deactivate user error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL(path.Join("users", username, "deactivate")).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("deactivate user error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("deactivate user", res)
+	}
+
+	return nil
+}
+
+// DisconnectUser 强制用户下线 (所有设备)
+// username: 用户 ID
+func (em *Easemob) DisconnectUser(ctx context.Context, username string) error {
+	if len(username) < 1 {
+		return errors.New("disconnect user error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("users", username, "disconnect")).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("disconnect user error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("disconnect user", res)
+	}
+
+	return nil
+}
+
+// DeleteUserMetadata 删除用户的全部用户属性
+// username: 用户 ID
+func (em *Easemob) DeleteUserMetadata(ctx context.Context, username string) error {
+	if len(username) < 1 {
+		return errors.New("delete user metadata error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("metadata/user", username)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("delete user metadata error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("delete user metadata", res)
+	}
+
+	return nil
+}
+
+type PushBinding struct {
+	DeviceId     string `json:"device_id"`     // 设备 ID
+	NotifierName string `json:"notifier_name"` // 推送证书名称
+	DeviceToken  string `json:"device_token"`  // 推送 Token, 为空时表示解绑
+}
+
+// GetUserPushBindings 获取用户绑定的推送设备
+// username: 用户 ID
+func (em *Easemob) GetUserPushBindings(ctx context.Context, username string) ([]*PushBinding, error) {
+	if len(username) < 1 {
+		return nil, errors.New("get user push bindings error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL(path.Join("users", username, "push/binding")).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get user push bindings error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get user push bindings", res)
+	}
+
+	resp := &RespCommon[[]*PushBinding]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get user push bindings error: %w", e)
+	}
+
+	return resp.Data, nil
+}
+
+// UnbindUserPushDevice 解绑用户的推送设备
+// username: 用户 ID, deviceId: 设备 ID, notifierName: 推送证书名称
+func (em *Easemob) UnbindUserPushDevice(ctx context.Context, username, deviceId, notifierName string) error {
+	if len(username) < 1 || len(deviceId) < 1 || len(notifierName) < 1 {
+		return errors.New("unbind user push device error: invalid params")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Put(em.GetURL(path.Join("users", username, "push/binding")).String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(&PushBinding{
+			DeviceId:     deviceId,
+			NotifierName: notifierName,
+		}))
+	if e != nil {
+		return fmt.Errorf("unbind user push device error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("unbind user push device", res)
+	}
+
+	return nil
+}
+
+type LoginRecord struct {
+	Device    string    // 设备名称
+	OS        string    // 操作系统
+	IP        string    // 登录 IP, 服务端未提供时为空
+	LoginTime time.Time // 登录时间
+}
+
+type loginRecordResp struct {
+	Device    string `json:"device"`
+	OS        string `json:"os"`
+	IP        string `json:"ip"`
+	LoginTime int64  `json:"loginTime"` // Unix 时间戳, 单位为毫秒
+}
+
+// GetUserLoginHistory 获取用户最近的登录设备记录, 用于安全审计
+// 集群未开通该功能时返回的错误满足 errors.Is(e, ErrNotSupported)
+// username: 用户 ID, limit: 最多返回的记录数
+func (em *Easemob) GetUserLoginHistory(ctx context.Context, username string, limit int) ([]LoginRecord, error) {
+	if len(username) < 1 {
+		return nil, errors.New("get user login history error: username is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+
+	res, e := em.end(withQuery(c.Get(em.GetURL(path.Join("users", username, "login_history")).String()), q).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get user login history error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get user login history", res)
+	}
+
+	resp := &RespCommon[[]*loginRecordResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get user login history error: %w", e)
+	}
+
+	records := make([]LoginRecord, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		records = append(records, LoginRecord{
+			Device:    v.Device,
+			OS:        v.OS,
+			IP:        v.IP,
+			LoginTime: time.UnixMilli(v.LoginTime),
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,143 @@
+package easemob
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// UserEntity 用户实体, 所有用户相关接口共用
+type UserEntity struct {
+	Uuid      string // 用户的 UUID, 系统内为每个用户自动生成的唯一标识
+	Type      string // 实体类型, 固定为 user
+	Created   int64  // 用户注册时间, Unix 时间戳, 单位为毫秒
+	Modified  int64  // 最近一次修改用户信息的时间, Unix 时间戳, 单位为毫秒
+	Username  string // 用户 ID
+	Activated bool   // 用户是否为正常状态, false 表示已被封禁
+	Nickname  string // 推送消息时, 在消息推送通知栏内显示的用户昵称
+
+	NotificationDisplayStyle      int      // 消息推送方式: 0 仅通知, 1 通知以及消息详情
+	NotificationNoDisturbing      bool     // 是否开启免打扰
+	NotificationNoDisturbingStart int      // 免打扰开始时间 (小时)
+	NotificationNoDisturbingEnd   int      // 免打扰结束时间 (小时)
+	NotifierName                  string   // 推送证书名称
+	DeviceToken                   string   // 推送 Token
+	Resources                     []string // 已登录的设备资源
+}
+
+// CreatedTime 用户注册时间
+func (u *UserEntity) CreatedTime() time.Time {
+	return time.UnixMilli(u.Created)
+}
+
+// ModifiedTime 最近一次修改用户信息的时间
+func (u *UserEntity) ModifiedTime() time.Time {
+	return time.UnixMilli(u.Modified)
+}
+
+type userEntityJSON struct {
+	Uuid      string   `json:"uuid"`
+	Type      string   `json:"type"`
+	Created   flexInt  `json:"created"`
+	Modified  flexInt  `json:"modified"`
+	Username  string   `json:"username"`
+	Activated flexBool `json:"activated"`
+	Nickname  string   `json:"nickname"`
+
+	NotificationDisplayStyle      flexInt  `json:"notification_display_style"`
+	NotificationNoDisturbing      flexBool `json:"notification_no_disturbing"`
+	NotificationNoDisturbingStart flexInt  `json:"notification_no_disturbing_start"`
+	NotificationNoDisturbingEnd   flexInt  `json:"notification_no_disturbing_end"`
+	NotifierName                  string   `json:"notifier_name"`
+	DeviceToken                   string   `json:"device_token"`
+	Resources                     []string `json:"resources"`
+}
+
+// UnmarshalJSON 兼容 Easemob 有时以字符串, 有时以数字返回的字段
+func (u *UserEntity) UnmarshalJSON(data []byte) error {
+	v := &userEntityJSON{}
+	if e := json.Unmarshal(data, v); e != nil {
+		return e
+	}
+
+	*u = UserEntity{
+		Uuid:      v.Uuid,
+		Type:      v.Type,
+		Created:   int64(v.Created),
+		Modified:  int64(v.Modified),
+		Username:  v.Username,
+		Activated: bool(v.Activated),
+		Nickname:  v.Nickname,
+
+		NotificationDisplayStyle:      int(v.NotificationDisplayStyle),
+		NotificationNoDisturbing:      bool(v.NotificationNoDisturbing),
+		NotificationNoDisturbingStart: int(v.NotificationNoDisturbingStart),
+		NotificationNoDisturbingEnd:   int(v.NotificationNoDisturbingEnd),
+		NotifierName:                  v.NotifierName,
+		DeviceToken:                   v.DeviceToken,
+		Resources:                     v.Resources,
+	}
+
+	return nil
+}
+
+// MarshalJSON 按 Easemob 的字段名输出
+func (u *UserEntity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&userEntityJSON{
+		Uuid:      u.Uuid,
+		Type:      u.Type,
+		Created:   flexInt(u.Created),
+		Modified:  flexInt(u.Modified),
+		Username:  u.Username,
+		Activated: flexBool(u.Activated),
+		Nickname:  u.Nickname,
+
+		NotificationDisplayStyle:      flexInt(u.NotificationDisplayStyle),
+		NotificationNoDisturbing:      flexBool(u.NotificationNoDisturbing),
+		NotificationNoDisturbingStart: flexInt(u.NotificationNoDisturbingStart),
+		NotificationNoDisturbingEnd:   flexInt(u.NotificationNoDisturbingEnd),
+		NotifierName:                  u.NotifierName,
+		DeviceToken:                   u.DeviceToken,
+		Resources:                     u.Resources,
+	})
+}
+
+// flexInt 兼容数字和字符串形式的整数
+type flexInt int64
+
+func (i *flexInt) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) < 1 || string(data) == "null" {
+		*i = 0
+		return nil
+	}
+
+	v, e := strconv.ParseInt(string(data), 10, 64)
+	if e != nil {
+		return fmt.Errorf("invalid int %s: %w", data, e)
+	}
+
+	*i = flexInt(v)
+	return nil
+}
+
+// flexBool 兼容布尔值和字符串形式的布尔值
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) < 1 || string(data) == "null" {
+		*b = false
+		return nil
+	}
+
+	v, e := strconv.ParseBool(string(data))
+	if e != nil {
+		return fmt.Errorf("invalid bool %s: %w", data, e)
+	}
+
+	*b = flexBool(v)
+	return nil
+}
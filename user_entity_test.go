@@ -0,0 +1,215 @@
+package easemob
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 各接口返回的同一个用户, 字段类型不完全一致
+const (
+	createUserFixture = `{
+		"action": "post",
+		"entities": [{
+			"uuid": "0ffe2d80-xxxx",
+			"type": "user",
+			"created": 1542795196504,
+			"modified": 1542795196504,
+			"username": "alice",
+			"activated": true,
+			"nickname": "Alice"
+		}],
+		"timestamp": 1542795196515,
+		"duration": 0
+	}`
+
+	getUserFixture = `{
+		"action": "get",
+		"entities": [{
+			"uuid": "0ffe2d80-xxxx",
+			"type": "user",
+			"created": "1542795196504",
+			"modified": "1542795200000",
+			"username": "alice",
+			"activated": "true",
+			"nickname": "Alice",
+			"notification_display_style": "1",
+			"notification_no_disturbing": "true",
+			"notification_no_disturbing_start": 22,
+			"notification_no_disturbing_end": "7",
+			"notifier_name": "apns-prod",
+			"device_token": "token-1",
+			"resources": ["android_1", "ios_2"]
+		}],
+		"count": 1
+	}`
+
+	listUsersFixture = `{
+		"action": "get",
+		"entities": [{
+			"uuid": "0ffe2d80-xxxx",
+			"type": "user",
+			"created": 1542795196504,
+			"modified": 1542795200000,
+			"username": "alice",
+			"activated": true,
+			"nickname": "Alice",
+			"notification_display_style": 1,
+			"notification_no_disturbing": true,
+			"notification_no_disturbing_start": "22",
+			"notification_no_disturbing_end": 7,
+			"notifier_name": "apns-prod",
+			"device_token": "token-1",
+			"resources": ["android_1", "ios_2"]
+		}, {
+			"uuid": "1a2b3c4d-xxxx",
+			"type": "user",
+			"created": "1542795300000",
+			"modified": null,
+			"username": "bob",
+			"activated": "false"
+		}],
+		"cursor": "next-cursor",
+		"count": 2
+	}`
+)
+
+func TestUserEntityFixtures(t *testing.T) {
+	mux := http.NewServeMux()
+	serve := func(fixture string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(fixture))
+		}
+	}
+	mux.HandleFunc("POST /org/app/users", serve(createUserFixture))
+	mux.HandleFunc("GET /org/app/users/alice", serve(getUserFixture))
+	mux.HandleFunc("GET /org/app/users", serve(listUsersFixture))
+	mux.HandleFunc("DELETE /org/app/users/alice", serve(getUserFixture))
+
+	em := newTestEasemob(t, mux)
+	ctx := context.Background()
+
+	full := &UserEntity{
+		Uuid:      "0ffe2d80-xxxx",
+		Type:      "user",
+		Created:   1542795196504,
+		Modified:  1542795200000,
+		Username:  "alice",
+		Activated: true,
+		Nickname:  "Alice",
+
+		NotificationDisplayStyle:      1,
+		NotificationNoDisturbing:      true,
+		NotificationNoDisturbingStart: 22,
+		NotificationNoDisturbingEnd:   7,
+		NotifierName:                  "apns-prod",
+		DeviceToken:                   "token-1",
+		Resources:                     []string{"android_1", "ios_2"},
+	}
+
+	created, e := em.CreateUser(ctx, "alice", "secret", "Alice")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := &UserEntity{
+		Uuid:      full.Uuid,
+		Type:      full.Type,
+		Created:   full.Created,
+		Modified:  full.Created,
+		Username:  full.Username,
+		Activated: true,
+		Nickname:  full.Nickname,
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("create user: unexpected entity %+v", created)
+	}
+
+	got, e := em.GetUser(ctx, "alice")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(got, full) {
+		t.Errorf("get user: unexpected entity %+v", got)
+	}
+
+	users, cursor, e := em.ListUsers(ctx, 2, "")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if cursor != "next-cursor" || len(users) != 2 {
+		t.Fatalf("list users: unexpected result %d users, cursor %q", len(users), cursor)
+	}
+
+	if !reflect.DeepEqual(users[0], full) {
+		t.Errorf("list users: unexpected entity %+v", users[0])
+	}
+
+	if users[1].Username != "bob" || users[1].Activated || users[1].Created != 1542795300000 || users[1].Modified != 0 {
+		t.Errorf("list users: unexpected entity %+v", users[1])
+	}
+
+	deleted, e := em.DeleteUser(ctx, "alice")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(deleted, full) {
+		t.Errorf("delete user: unexpected entity %+v", deleted)
+	}
+
+	if !got.CreatedTime().Equal(time.UnixMilli(1542795196504)) || !got.ModifiedTime().Equal(time.UnixMilli(1542795200000)) {
+		t.Errorf("unexpected times: %s, %s", got.CreatedTime(), got.ModifiedTime())
+	}
+}
+
+func TestListUsersPaging(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("limit") != "2" || q.Get("cursor") != "c1" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"entities": [], "count": 0}`))
+	})
+
+	em := newTestEasemob(t, mux)
+
+	users, cursor, e := em.ListUsers(context.Background(), 2, "c1")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if len(users) != 0 || len(cursor) > 0 {
+		t.Errorf("unexpected result %d users, cursor %q", len(users), cursor)
+	}
+}
+
+func TestUserEntityRoundTrip(t *testing.T) {
+	resp := &userEntitiesResp{}
+	if e := json.Unmarshal([]byte(getUserFixture), resp); e != nil {
+		t.Fatal(e)
+	}
+
+	data, e := json.Marshal(resp.Entities[0])
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	decoded := &UserEntity{}
+	if e := json.Unmarshal(data, decoded); e != nil {
+		t.Fatal(e)
+	}
+
+	if !reflect.DeepEqual(decoded, resp.Entities[0]) {
+		t.Errorf("round trip lost data: %+v", decoded)
+	}
+}
@@ -0,0 +1,318 @@
+package easemob
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetUserJoinedGroupsPaged(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice/joined_chatgroups", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("pagesize") != "2" {
+			t.Errorf("unexpected pagesize: %q", q.Get("pagesize"))
+		}
+
+		data := map[string][]map[string]string{
+			"1": {{"groupid": "g1", "groupname": "一组"}, {"groupid": "g2", "groupname": "二组"}},
+			"2": {{"groupid": "g3", "groupname": "三组"}},
+		}[q.Get("pagenum")]
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data, "count": 3})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	page, e := em.GetUserJoinedGroups(context.Background(), "alice", 1, 2)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := []*JoinedGroup{{GroupId: "g1", GroupName: "一组"}, {GroupId: "g2", GroupName: "二组"}}
+	// Count 为服务端返回的总数, 而不是当前页的数量
+	if page.Count != 3 || !reflect.DeepEqual(page.Groups, want) {
+		t.Errorf("unexpected page 1: %+v", page)
+	}
+
+	page, e = em.GetUserJoinedGroups(context.Background(), "alice", 2, 2)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if page.Count != 3 || len(page.Groups) != 1 || page.Groups[0].GroupId != "g3" {
+		t.Errorf("unexpected page 2: %+v", page)
+	}
+}
+
+func TestGetUserJoinedChatrooms(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice/joined_chatrooms", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]string{{"id": "r1", "name": "大厅"}, {"id": "r2", "name": "直播间"}},
+		})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	rooms, e := em.GetUserJoinedChatrooms(context.Background(), "alice")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := []JoinedRoom{{Id: "r1", Name: "大厅"}, {Id: "r2", Name: "直播间"}}
+	if !reflect.DeepEqual(rooms, want) {
+		t.Errorf("unexpected rooms: %+v", rooms)
+	}
+}
+
+func TestGetUserJoinedNothing(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/loner/joined_chatgroups", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": []interface{}{}})
+	})
+	mux.HandleFunc("GET /org/app/users/loner/joined_chatrooms", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": nil})
+	})
+
+	em := newTestEasemob(t, mux)
+
+	page, e := em.GetUserJoinedGroups(context.Background(), "loner", 1, 10)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if page.Count != 0 || len(page.Groups) != 0 {
+		t.Errorf("unexpected groups: %+v", page)
+	}
+
+	rooms, e := em.GetUserJoinedChatrooms(context.Background(), "loner")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if rooms == nil || len(rooms) != 0 {
+		t.Errorf("unexpected rooms: %#v", rooms)
+	}
+}
+
+func TestUpdateUserProfilePartial(t *testing.T) {
+	hits := map[string]int{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /org/app/metadata/user/alice", func(w http.ResponseWriter, r *http.Request) {
+		hits["metadata"]++
+
+		if e := r.ParseForm(); e != nil {
+			t.Error(e)
+		}
+
+		if r.PostForm.Get("nickname") != "Alice" || r.PostForm.Has("avatarurl") {
+			t.Errorf("unexpected metadata form: %v", r.PostForm)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"nickname": "Alice"}})
+	})
+	mux.HandleFunc("PUT /org/app/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		hits["user"]++
+
+		req := &pushNicknameReq{}
+		decodeBody(t, r, req)
+		if req.Nickname != "Alice Push" {
+			t.Errorf("unexpected push nickname: %q", req.Nickname)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+
+	em := newTestEasemob(t, mux)
+	nickname, pushNickname := "Alice", "Alice Push"
+
+	if e := em.UpdateUserProfile(context.Background(), "alice", UserProfileUpdate{Nickname: &nickname}); e != nil {
+		t.Fatal(e)
+	}
+
+	if hits["metadata"] != 1 || hits["user"] != 0 {
+		t.Errorf("metadata only: unexpected hits %v", hits)
+	}
+
+	if e := em.UpdateUserProfile(context.Background(), "alice", UserProfileUpdate{PushNickname: &pushNickname}); e != nil {
+		t.Fatal(e)
+	}
+
+	if hits["metadata"] != 1 || hits["user"] != 1 {
+		t.Errorf("push nickname only: unexpected hits %v", hits)
+	}
+
+	if e := em.UpdateUserProfile(context.Background(), "alice", UserProfileUpdate{}); e != nil {
+		t.Fatal(e)
+	}
+
+	if hits["metadata"] != 1 || hits["user"] != 1 {
+		t.Errorf("empty update: unexpected hits %v", hits)
+	}
+}
+
+func TestUpdateUserProfileSecondCallFails(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /org/app/metadata/user/alice", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+	mux.HandleFunc("PUT /org/app/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusBadRequest, "illegal_argument", "nickname is too long")
+	})
+
+	em := newTestEasemob(t, mux)
+	nickname, pushNickname := "Alice", "Alice Push"
+
+	e := em.UpdateUserProfile(context.Background(), "alice", UserProfileUpdate{
+		Nickname:     &nickname,
+		PushNickname: &pushNickname,
+	})
+
+	re := &ResponseError{}
+	if !errors.As(e, &re) {
+		t.Fatalf("expected response error, got %v", e)
+	}
+
+	if re.Op != "update user profile push nickname" || !errors.Is(e, ErrBadRequest) {
+		t.Errorf("failure attributed to the wrong step: %v", e)
+	}
+}
+
+func TestGetUsersMuteStatusPartialFailure(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/mutes/{username}", func(w http.ResponseWriter, r *http.Request) {
+		switch username := r.PathValue("username"); username {
+		case "u5":
+			writeError(w, http.StatusNotFound, "service_resource_not_found", "user u5 not found")
+		case "u13":
+			writeError(w, http.StatusInternalServerError, "internal_error", "try again later")
+		default:
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"data": map[string]interface{}{"userid": username, "chat": 60},
+			})
+		}
+	})
+
+	em := newTestEasemob(t, mux)
+	em.SetBatchConcurrency(4)
+
+	usernames := []string{}
+	for i := 0; i < 20; i++ {
+		usernames = append(usernames, "u"+strconv.Itoa(i))
+	}
+
+	statuses, e := em.GetUsersMuteStatus(context.Background(), usernames)
+	if e == nil {
+		t.Fatal("expected error for failed lookups")
+	}
+
+	if len(statuses) != 18 {
+		t.Errorf("unexpected status count: %d", len(statuses))
+	}
+
+	for _, username := range []string{"u5", "u13"} {
+		if _, ok := statuses[username]; ok {
+			t.Errorf("%s should not be in the result", username)
+		}
+
+		if !strings.Contains(e.Error(), username+":") {
+			t.Errorf("error does not mention %s: %v", username, e)
+		}
+	}
+
+	if !errors.Is(e, ErrNotFound) {
+		t.Errorf("expected joined error to contain not found: %v", e)
+	}
+
+	if s := statuses["u0"]; s == nil || s.Username != "u0" || s.Chat != 60 {
+		t.Errorf("unexpected status of u0: %+v", s)
+	}
+}
+
+func TestGetUsersMuteStatusCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var hits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/mutes/{username}", func(w http.ResponseWriter, r *http.Request) {
+		// 第一个请求发出后取消, 剩余用户不再等待并发槽位
+		hits.Add(1)
+		cancel()
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{}})
+	})
+
+	em := newTestEasemob(t, mux)
+	em.SetBatchConcurrency(1)
+
+	usernames := make([]string, 20)
+	for i := range usernames {
+		usernames[i] = "u" + strconv.Itoa(i)
+	}
+
+	_, e := em.GetUsersMuteStatus(ctx, usernames)
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", e)
+	}
+
+	if n := hits.Load(); n >= int32(len(usernames)) {
+		t.Errorf("all %d lookups were sent after cancellation", n)
+	}
+}
+
+func TestGetUserLoginHistory(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice/login_history", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "3" {
+			t.Errorf("unexpected limit: %q", r.URL.Query().Get("limit"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"action": "get",
+			"data": [
+				{"device": "iPhone 15", "os": "iOS 17.4", "ip": "203.0.113.7", "loginTime": 1710000000000},
+				{"device": "Pixel 8", "os": "Android 14", "loginTime": 1709990000000},
+				{"device": "Chrome", "os": "macOS", "ip": "198.51.100.2", "loginTime": 1709980000000}
+			]
+		}`))
+	})
+
+	em := newTestEasemob(t, mux)
+
+	records, e := em.GetUserLoginHistory(context.Background(), "alice", 3)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	want := []LoginRecord{
+		{Device: "iPhone 15", OS: "iOS 17.4", IP: "203.0.113.7", LoginTime: time.UnixMilli(1710000000000)},
+		{Device: "Pixel 8", OS: "Android 14", LoginTime: time.UnixMilli(1709990000000)},
+		{Device: "Chrome", OS: "macOS", IP: "198.51.100.2", LoginTime: time.UnixMilli(1709980000000)},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("unexpected records: %+v", records)
+	}
+}
+
+func TestGetUserLoginHistoryNotSupported(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/app/users/alice/login_history", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusForbidden, "service_not_open", "login history is not open")
+	})
+
+	em := newTestEasemob(t, mux)
+
+	_, e := em.GetUserLoginHistory(context.Background(), "alice", 0)
+	if !errors.Is(e, ErrNotSupported) {
+		t.Fatalf("expected not supported error, got %v", e)
+	}
+}
@@ -0,0 +1,377 @@
+package easemob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+	"uw/ureq"
+)
+
+// WebhookMaxBodySize 回调请求体的最大字节数
+const WebhookMaxBodySize = 1 << 20
+
+type EventType string
+
+const (
+	EventTypeSendMessage    EventType = "chat"           // 发送消息
+	EventTypeOfflineMessage EventType = "chat_offline"   // 离线消息
+	EventTypeRecallMessage  EventType = "recall"         // 撤回消息
+	EventTypeUserStatus     EventType = "userStatus"     // 用户上下线, Type() 会按状态转换为 EventTypeUserLogin 或 EventTypeUserLogout
+	EventTypeUserLogin      EventType = "login"          // 用户登录
+	EventTypeUserLogout     EventType = "logout"         // 用户登出
+	EventTypeGroupCreate    EventType = "group_create"   // 创建群组
+	EventTypeGroupDelete    EventType = "group_destroy"  // 解散群组
+	EventTypeGroupJoin      EventType = "group_join"     // 加入群组
+	EventTypeGroupLeave     EventType = "group_leave"    // 退出或被移出群组
+	EventTypeRoomCreate     EventType = "room_create"    // 创建聊天室
+	EventTypeRoomDelete     EventType = "room_destroy"   // 解散聊天室
+	EventTypeRoomJoin       EventType = "room_join"      // 加入聊天室
+	EventTypeRoomLeave      EventType = "room_leave"     // 退出或被移出聊天室
+	EventTypeContactAdded   EventType = "contact_add"    // 添加好友
+	EventTypeContactRemoved EventType = "contact_remove" // 删除好友
+	EventTypeMuted          EventType = "mute"           // 禁言
+	EventTypeUnmuted        EventType = "unmute"         // 解除禁言
+)
+
+type WebhookEventCommon struct {
+	CallId    string    `json:"callId"`    // 回调 ID, 重试时不变, 可用于去重
+	EventType EventType `json:"eventType"` // 事件类型, 建议使用 Type()
+	Timestamp int64     `json:"timestamp"` // 事件发生时间, Unix 时间戳, 单位为毫秒
+	AppKey    string    `json:"appkey"`    // App Key, 格式为 orgName#appName
+	Host      string    `json:"host"`      // 发出回调的服务器
+}
+
+// WebhookEvent 回调事件, 根据 Type() 只有对应的一个内容字段不为 nil
+type WebhookEvent struct {
+	WebhookEventCommon
+
+	Message    *WebhookMessage      `json:"-"` // 消息事件: EventTypeSendMessage, EventTypeOfflineMessage
+	Recall     *WebhookRecall       `json:"-"` // 撤回事件: EventTypeRecallMessage
+	UserStatus *WebhookUserStatus   `json:"-"` // 用户事件: EventTypeUserStatus, EventTypeUserLogin, EventTypeUserLogout
+	Group      *WebhookGroupEvent   `json:"-"` // 群组和聊天室事件: EventTypeGroup*, EventTypeRoom*
+	Contact    *WebhookContactEvent `json:"-"` // 好友事件: EventTypeContactAdded, EventTypeContactRemoved
+	Mute       *WebhookMuteEvent    `json:"-"` // 禁言事件: EventTypeMuted, EventTypeUnmuted
+
+	Raw json.RawMessage `json:"-"` // 原始回调内容
+}
+
+// Type 事件类型, EventTypeUserStatus 会按状态转换为 EventTypeUserLogin 或 EventTypeUserLogout
+func (ev *WebhookEvent) Type() EventType {
+	if ev.EventType == EventTypeUserStatus && ev.UserStatus != nil {
+		if ev.UserStatus.Status == "online" {
+			return EventTypeUserLogin
+		}
+
+		return EventTypeUserLogout
+	}
+
+	return ev.EventType
+}
+
+type WebhookMessage struct {
+	MsgId    string          `json:"msg_id"`    // 消息 ID
+	From     string          `json:"from"`      // 发送方
+	To       string          `json:"to"`        // 接收方
+	ChatType ChatType        `json:"chat_type"` // 会话类型
+	GroupId  string          `json:"group_id"`  // 群组或聊天室 ID, 单聊时为空
+	Payload  json.RawMessage `json:"payload"`   // 消息内容 (bodies 和 ext)
+}
+
+type WebhookRecall struct {
+	MsgId    string   `json:"recall_id"` // 被撤回的消息 ID
+	From     string   `json:"from"`      // 撤回操作人
+	To       string   `json:"to"`        // 接收方
+	ChatType ChatType `json:"chat_type"` // 会话类型
+}
+
+type WebhookUserStatus struct {
+	User    string `json:"user"`    // 用户 ID, 格式为 appkey_username/resource
+	Status  string `json:"status"`  // 状态, online 或 offline
+	Reason  string `json:"reason"`  // 状态变化的原因, 例如 login, logout, replaced
+	OS      string `json:"os"`      // 客户端系统
+	Version string `json:"version"` // 客户端版本
+}
+
+type WebhookGroupEvent struct {
+	GroupId  string   `json:"group_id"`  // 群组或聊天室 ID
+	ChatType ChatType `json:"chat_type"` // groupchat 或 chatroom
+	From     string   `json:"from"`      // 操作人
+	Members  []string `json:"members"`   // 受影响的成员
+	Reason   string   `json:"reason"`    // 离开的原因, 例如 leave, kicked
+}
+
+type WebhookContactEvent struct {
+	From string `json:"from"` // 操作人
+	To   string `json:"to"`   // 对方用户 ID
+}
+
+type WebhookMuteEvent struct {
+	From     string   `json:"from"`      // 操作人
+	GroupId  string   `json:"group_id"`  // 群组或聊天室 ID, 全局禁言时为空
+	ChatType ChatType `json:"chat_type"` // 会话类型
+	Members  []string `json:"members"`   // 被禁言或解除禁言的成员
+	Duration int64    `json:"duration"`  // 禁言时长, 单位为毫秒, -1 表示永久
+}
+
+func webhookMessagePayload(ev *WebhookEvent) interface{} {
+	ev.Message = &WebhookMessage{}
+	return ev.Message
+}
+
+func webhookGroupPayload(ev *WebhookEvent) interface{} {
+	ev.Group = &WebhookGroupEvent{}
+	return ev.Group
+}
+
+func webhookUserStatusPayload(ev *WebhookEvent) interface{} {
+	ev.UserStatus = &WebhookUserStatus{}
+	return ev.UserStatus
+}
+
+func webhookContactPayload(ev *WebhookEvent) interface{} {
+	ev.Contact = &WebhookContactEvent{}
+	return ev.Contact
+}
+
+func webhookMutePayload(ev *WebhookEvent) interface{} {
+	ev.Mute = &WebhookMuteEvent{}
+	return ev.Mute
+}
+
+// webhookPayloads 各事件类型对应的内容字段
+var webhookPayloads = map[EventType]func(ev *WebhookEvent) interface{}{
+	EventTypeSendMessage:    webhookMessagePayload,
+	EventTypeOfflineMessage: webhookMessagePayload,
+	EventTypeRecallMessage: func(ev *WebhookEvent) interface{} {
+		ev.Recall = &WebhookRecall{}
+		return ev.Recall
+	},
+	EventTypeUserStatus:     webhookUserStatusPayload,
+	EventTypeUserLogin:      webhookUserStatusPayload,
+	EventTypeUserLogout:     webhookUserStatusPayload,
+	EventTypeGroupCreate:    webhookGroupPayload,
+	EventTypeGroupDelete:    webhookGroupPayload,
+	EventTypeGroupJoin:      webhookGroupPayload,
+	EventTypeGroupLeave:     webhookGroupPayload,
+	EventTypeRoomCreate:     webhookGroupPayload,
+	EventTypeRoomDelete:     webhookGroupPayload,
+	EventTypeRoomJoin:       webhookGroupPayload,
+	EventTypeRoomLeave:      webhookGroupPayload,
+	EventTypeContactAdded:   webhookContactPayload,
+	EventTypeContactRemoved: webhookContactPayload,
+	EventTypeMuted:          webhookMutePayload,
+	EventTypeUnmuted:        webhookMutePayload,
+}
+
+// ParseWebhookEvent 解析回调内容, 未知的事件类型只解析公共字段, 原始内容保存在 Raw 中
+func ParseWebhookEvent(data []byte) (*WebhookEvent, error) {
+	ev := &WebhookEvent{}
+	if e := json.Unmarshal(data, ev); e != nil {
+		return nil, fmt.Errorf("parse webhook event error: %w", e)
+	}
+
+	if len(ev.EventType) < 1 {
+		return nil, errors.New("parse webhook event error: event type is empty")
+	}
+
+	if payload, ok := webhookPayloads[ev.EventType]; ok {
+		if e := json.Unmarshal(data, payload(ev)); e != nil {
+			return nil, fmt.Errorf("parse webhook event error: %w", e)
+		}
+	}
+
+	ev.Raw = data
+	return ev, nil
+}
+
+// VerifyWebhookSignature 校验回调签名, 签名为请求体的 HMAC-SHA1 (十六进制)
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	sig, e := hex.DecodeString(signature)
+	if e != nil {
+		return false
+	}
+
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// NewWebhookHandler 创建接收 Easemob 回调的 http.Handler
+// 请求的 msg_signature 参数校验失败时返回 401, handler 返回错误时返回 500, Easemob 会按规则重试
+// secret: 回调规则中配置的密钥, handler: 事件处理函数
+func NewWebhookHandler(secret string, handler func(*WebhookEvent) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, e := io.ReadAll(http.MaxBytesReader(w, r.Body, WebhookMaxBodySize))
+		if e != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if !VerifyWebhookSignature(secret, body, r.URL.Query().Get("msg_signature")) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		ev, e := ParseWebhookEvent(body)
+		if e != nil {
+			http.Error(w, e.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if e := handler(ev); e != nil {
+			http.Error(w, e.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+type WebhookConfig struct {
+	URL        string      `json:"url"`        // 回调地址
+	Secret     string      `json:"secret"`     // 签名密钥, 与 NewWebhookHandler 的 secret 一致
+	EventTypes []EventType `json:"eventTypes"` // 需要回调的事件类型
+	Enabled    bool        `json:"enabled"`    // 是否启用
+	MaxRetries int         `json:"maxRetries"` // 回调失败时的最大重试次数
+}
+
+type WebhookInfo struct {
+	RuleId     string      // 回调规则 ID
+	URL        string      // 回调地址
+	EventTypes []EventType // 需要回调的事件类型
+	Enabled    bool        // 是否启用
+	MaxRetries int         // 回调失败时的最大重试次数
+	CreatedAt  time.Time   // 创建时间
+}
+
+type webhookInfoResp struct {
+	RuleId     string      `json:"ruleId"`
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"eventTypes"`
+	Enabled    bool        `json:"enabled"`
+	MaxRetries int         `json:"maxRetries"`
+	Created    int64       `json:"created"` // Unix 时间戳, 单位为毫秒
+}
+
+func (r *webhookInfoResp) info() *WebhookInfo {
+	return &WebhookInfo{
+		RuleId:     r.RuleId,
+		URL:        r.URL,
+		EventTypes: r.EventTypes,
+		Enabled:    r.Enabled,
+		MaxRetries: r.MaxRetries,
+		CreatedAt:  time.UnixMilli(r.Created),
+	}
+}
+
+// RegisterWebhook 创建回调规则
+// cfg: 回调规则配置
+func (em *Easemob) RegisterWebhook(ctx context.Context, cfg *WebhookConfig) (*WebhookInfo, error) {
+	if cfg == nil || len(cfg.URL) < 1 {
+		return nil, errors.New("register webhook error: url is empty")
+	}
+
+	if len(cfg.EventTypes) < 1 {
+		return nil, errors.New("register webhook error: event types is empty")
+	}
+
+	if cfg.MaxRetries < 0 {
+		return nil, errors.New("register webhook error: max retries must not be negative")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Post(em.GetURL("callbacks").String()).
+		Set(ureq.ContentType, "application/json").
+		Set(ureq.Accept, "application/json").
+		Send(cfg))
+	if e != nil {
+		return nil, fmt.Errorf("register webhook error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("register webhook", res)
+	}
+
+	resp := &RespCommon[*webhookInfoResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("register webhook error: %w", e)
+	}
+
+	if resp.Data == nil || len(resp.Data.RuleId) < 1 {
+		return nil, errors.New("register webhook error: rule id is empty")
+	}
+
+	return resp.Data.info(), nil
+}
+
+// DeregisterWebhook 删除回调规则
+// ruleId: 回调规则 ID
+func (em *Easemob) DeregisterWebhook(ctx context.Context, ruleId string) error {
+	if len(ruleId) < 1 {
+		return errors.New("deregister webhook error: rule id is empty")
+	}
+
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Delete(em.GetURL(path.Join("callbacks", ruleId)).String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return fmt.Errorf("deregister webhook error: %w", e)
+	}
+
+	if !res.OK() {
+		return newResponseError("deregister webhook", res)
+	}
+
+	return nil
+}
+
+// GetWebhooks 获取所有回调规则
+func (em *Easemob) GetWebhooks(ctx context.Context) ([]*WebhookInfo, error) {
+	c, e := em.GetAccessClient(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("get client error: %w", e)
+	}
+
+	res, e := em.end(c.Get(em.GetURL("callbacks").String()).
+		Set(ureq.Accept, "application/json"))
+	if e != nil {
+		return nil, fmt.Errorf("get webhooks error: %w", e)
+	}
+
+	if !res.OK() {
+		return nil, newResponseError("get webhooks", res)
+	}
+
+	resp := &RespCommon[[]*webhookInfoResp]{}
+	if e = res.JSON(resp); e != nil {
+		return nil, fmt.Errorf("get webhooks error: %w", e)
+	}
+
+	infos := make([]*WebhookInfo, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		infos = append(infos, v.info())
+	}
+
+	return infos, nil
+}
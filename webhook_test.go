@@ -0,0 +1,143 @@
+package easemob
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+func signWebhook(body []byte) string {
+	mac := hmac.New(sha1.New, []byte(testWebhookSecret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func serveWebhook(h http.Handler, method string, body []byte, signature string) *httptest.ResponseRecorder {
+	target := "/webhook"
+	if len(signature) > 0 {
+		target += "?msg_signature=" + url.QueryEscape(signature)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, bytes.NewReader(body)))
+	return w
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var events []*WebhookEvent
+	h := NewWebhookHandler(testWebhookSecret, func(ev *WebhookEvent) error {
+		events = append(events, ev)
+		return nil
+	})
+
+	body := []byte(`{
+		"callId": "call-1",
+		"eventType": "chat",
+		"timestamp": 1710000000000,
+		"appkey": "org#app",
+		"msg_id": "m1",
+		"from": "alice",
+		"to": "bob",
+		"chat_type": "chat",
+		"payload": {"bodies": [{"type": "txt", "msg": "hi"}]}
+	}`)
+
+	if w := serveWebhook(h, http.MethodPost, body, signWebhook(body)); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("unexpected event count: %d", len(events))
+	}
+
+	ev := events[0]
+	if ev.Type() != EventTypeSendMessage || ev.CallId != "call-1" || ev.Message == nil || ev.Message.MsgId != "m1" || ev.Message.From != "alice" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+
+	if !bytes.Equal(ev.Raw, body) {
+		t.Errorf("unexpected raw body: %s", ev.Raw)
+	}
+}
+
+func TestWebhookHandlerRejected(t *testing.T) {
+	h := NewWebhookHandler(testWebhookSecret, func(ev *WebhookEvent) error {
+		t.Errorf("unexpected event: %+v", ev)
+		return nil
+	})
+
+	body := []byte(`{"callId": "call-1", "eventType": "chat"}`)
+	large := []byte(`{"callId": "call-1", "eventType": "chat", "pad": "` + strings.Repeat("x", WebhookMaxBodySize) + `"}`)
+
+	cases := []struct {
+		name      string
+		method    string
+		body      []byte
+		signature string
+		status    int
+	}{
+		{"missing signature", http.MethodPost, body, "", http.StatusUnauthorized},
+		{"bad signature", http.MethodPost, body, signWebhook([]byte("other")), http.StatusUnauthorized},
+		{"non-hex signature", http.MethodPost, body, "not-hex", http.StatusUnauthorized},
+		{"body too large", http.MethodPost, large, signWebhook(large), http.StatusBadRequest},
+		{"get", http.MethodGet, body, signWebhook(body), http.StatusMethodNotAllowed},
+		{"invalid event", http.MethodPost, []byte(`{}`), signWebhook([]byte(`{}`)), http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if w := serveWebhook(h, c.method, c.body, c.signature); w.Code != c.status {
+				t.Errorf("unexpected status: %d, want %d", w.Code, c.status)
+			}
+		})
+	}
+}
+
+func TestWebhookHandlerError(t *testing.T) {
+	h := NewWebhookHandler(testWebhookSecret, func(ev *WebhookEvent) error {
+		return errors.New("database unavailable")
+	})
+
+	body := []byte(`{"callId": "call-1", "eventType": "userStatus", "user": "org#app_alice/ios", "status": "online"}`)
+
+	// handler 返回错误时返回 500, Easemob 会重试
+	if w := serveWebhook(h, http.MethodPost, body, signWebhook(body)); w.Code != http.StatusInternalServerError {
+		t.Errorf("unexpected status: %d", w.Code)
+	}
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	cases := []struct {
+		body  string
+		typ   EventType
+		check func(ev *WebhookEvent) bool
+	}{
+		{`{"eventType": "userStatus", "status": "online", "user": "alice"}`, EventTypeUserLogin, func(ev *WebhookEvent) bool { return ev.UserStatus != nil }},
+		{`{"eventType": "userStatus", "status": "offline", "user": "alice"}`, EventTypeUserLogout, func(ev *WebhookEvent) bool { return ev.UserStatus != nil }},
+		{`{"eventType": "recall", "recall_id": "m1"}`, EventTypeRecallMessage, func(ev *WebhookEvent) bool { return ev.Recall != nil && ev.Recall.MsgId == "m1" }},
+		{`{"eventType": "group_join", "group_id": "g1", "members": ["bob"]}`, EventTypeGroupJoin, func(ev *WebhookEvent) bool { return ev.Group != nil && ev.Group.GroupId == "g1" }},
+		{`{"eventType": "contact_add", "from": "alice", "to": "bob"}`, EventTypeContactAdded, func(ev *WebhookEvent) bool { return ev.Contact != nil && ev.Contact.To == "bob" }},
+		{`{"eventType": "mute", "members": ["bob"], "duration": -1}`, EventTypeMuted, func(ev *WebhookEvent) bool { return ev.Mute != nil && ev.Mute.Duration == -1 }},
+		{`{"eventType": "unknown_event"}`, "unknown_event", func(ev *WebhookEvent) bool { return ev.Message == nil && len(ev.Raw) > 0 }},
+	}
+
+	for _, c := range cases {
+		ev, e := ParseWebhookEvent([]byte(c.body))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		if ev.Type() != c.typ || !c.check(ev) {
+			t.Errorf("unexpected event for %s: %+v", c.body, ev)
+		}
+	}
+}